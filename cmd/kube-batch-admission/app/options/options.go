@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultQueue         = "default"
+	defaultListenAddress = ":8443"
+)
+
+// ServerOption is the main context object for the admission webhook server.
+type ServerOption struct {
+	Master        string
+	Kubeconfig    string
+	DefaultQueue  string
+	ListenAddress string
+	TLSCertFile   string
+	TLSPrivateKey string
+	PrintVersion  bool
+}
+
+// NewServerOption creates a new ServerOption with a default config.
+func NewServerOption() *ServerOption {
+	s := ServerOption{}
+	return &s
+}
+
+// AddFlags adds flags for a specific ServerOption to the specified FlagSet
+func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Master, "master", s.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
+	fs.StringVar(&s.Kubeconfig, "kubeconfig", s.Kubeconfig, "Path to kubeconfig file with authorization and master location information")
+	fs.StringVar(&s.DefaultQueue, "default-queue", defaultQueue, "The default queue name of the job")
+	fs.StringVar(&s.ListenAddress, "listen-address", defaultListenAddress, "The address to listen on for admission requests.")
+	fs.StringVar(&s.TLSCertFile, "tls-cert-file", s.TLSCertFile, "File containing the x509 certificate for HTTPS")
+	fs.StringVar(&s.TLSPrivateKey, "tls-private-key-file", s.TLSPrivateKey, "File containing the x509 private key matching --tls-cert-file")
+	fs.BoolVar(&s.PrintVersion, "version", false, "Show version and quit")
+}
+
+func (s *ServerOption) CheckOptionOrDie() error {
+	if s.PrintVersion {
+		return nil
+	}
+	if s.TLSCertFile == "" || s.TLSPrivateKey == "" {
+		return fmt.Errorf("tls-cert-file and tls-private-key-file must be specified")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kubernetes-sigs/kube-batch/cmd/kube-batch-admission/app/options"
+	"github.com/kubernetes-sigs/kube-batch/pkg/admission"
+	kbver "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
+	"github.com/kubernetes-sigs/kube-batch/pkg/version"
+)
+
+const apiVersion = "v1alpha1"
+
+func buildConfig(master, kubeconfig string) (*rest.Config, error) {
+	if master != "" || kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags(master, kubeconfig)
+	}
+	return rest.InClusterConfig()
+}
+
+// Run starts the admission webhook server and serves until it fails.
+func Run(opt *options.ServerOption) error {
+	if opt.PrintVersion {
+		version.PrintVersionAndExit(apiVersion)
+	}
+
+	config, err := buildConfig(opt.Master, opt.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	kbclient, err := kbver.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	server := admission.NewServer(kbclient, opt.DefaultQueue)
+
+	glog.V(3).Infof("Serving admission webhooks on %s", opt.ListenAddress)
+	return http.ListenAndServeTLS(opt.ListenAddress, opt.TLSCertFile, opt.TLSPrivateKey, server.Handler())
+}
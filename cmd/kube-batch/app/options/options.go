@@ -18,9 +18,12 @@ package options
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
 )
 
 const (
@@ -28,6 +31,13 @@ const (
 	defaultSchedulerPeriod = time.Second
 	defaultQueue           = "default"
 	defaultListenAddress   = ":8080"
+	defaultBindParallelism = 16
+	defaultBindQPS         = 50.0
+	defaultBindBurst       = 100
+	defaultBindTimeout     = 30 * time.Second
+	defaultAssumedTaskTTL  = 5 * time.Minute
+
+	defaultEvictionGracePeriod = -1
 )
 
 // ServerOption is the main context object for the controller manager.
@@ -41,7 +51,17 @@ type ServerOption struct {
 	LockObjectNamespace  string
 	DefaultQueue         string
 	PrintVersion         bool
+	ValidateConf         string
 	ListenAddress        string
+	BindParallelism      int
+	BindQPS              float32
+	BindBurst            int
+	BindTimeout          time.Duration
+	AssumedTaskTTL       time.Duration
+	EvictionMode         string
+	EvictionGracePeriod  int64
+	// EvictionGracePeriods is the grace period in seconds by PriorityClass name.
+	EvictionGracePeriods map[string]string
 }
 
 // NewServerOption creates a new CMServer with a default config.
@@ -63,8 +83,22 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 		"Start a leader election client and gain leadership before "+
 			"executing the main loop. Enable this when running replicated kube-batch for high availability")
 	fs.BoolVar(&s.PrintVersion, "version", false, "Show version and quit")
+	fs.StringVar(&s.ValidateConf, "validate-conf", "",
+		"Validate the scheduler configuration file and quit; the exit code is non-zero if it's invalid")
 	fs.StringVar(&s.LockObjectNamespace, "lock-object-namespace", s.LockObjectNamespace, "Define the namespace of the lock object")
 	fs.StringVar(&s.ListenAddress, "listen-address", defaultListenAddress, "The address to listen on for HTTP requests.")
+	fs.IntVar(&s.BindParallelism, "bind-parallelism", defaultBindParallelism, "The number of workers binding pods to nodes")
+	fs.Float32Var(&s.BindQPS, "bind-qps", defaultBindQPS, "The maximum QPS of binding requests to API server")
+	fs.IntVar(&s.BindBurst, "bind-burst", defaultBindBurst, "The maximum burst of binding requests to API server")
+	fs.DurationVar(&s.BindTimeout, "bind-timeout", defaultBindTimeout, "The timeout of binding requests to API server")
+	fs.DurationVar(&s.AssumedTaskTTL, "assumed-task-ttl", defaultAssumedTaskTTL,
+		"The time for tasks to wait for the binding to be confirmed, before they're moved back to Pending")
+	fs.StringVar(&s.EvictionMode, "eviction-mode", cache.EvictionModeDelete,
+		"How pods are evicted: 'delete' deletes pods directly, 'eviction' uses the Eviction API which honors PodDisruptionBudgets")
+	fs.Int64Var(&s.EvictionGracePeriod, "eviction-grace-period", defaultEvictionGracePeriod,
+		"The grace period in seconds of evicted pods; the grace period of pod is used if it's negative")
+	fs.StringToStringVar(&s.EvictionGracePeriods, "eviction-priority-class-grace-periods", s.EvictionGracePeriods,
+		"The grace period in seconds of evicted pods by PriorityClass, e.g. 'high=300,low=0'; it overrides --eviction-grace-period")
 }
 
 func (s *ServerOption) CheckOptionOrDie() error {
@@ -72,5 +106,36 @@ func (s *ServerOption) CheckOptionOrDie() error {
 		return fmt.Errorf("lock-object-namespace must not be nil when LeaderElection is enabled")
 	}
 
+	if s.BindParallelism <= 0 || s.BindQPS <= 0 || s.BindBurst <= 0 {
+		return fmt.Errorf("bind-parallelism, bind-qps and bind-burst must be greater than 0")
+	}
+
+	if s.BindTimeout <= 0 || s.AssumedTaskTTL <= 0 {
+		return fmt.Errorf("bind-timeout and assumed-task-ttl must be greater than 0")
+	}
+
+	if s.EvictionMode != cache.EvictionModeDelete && s.EvictionMode != cache.EvictionModeEviction {
+		return fmt.Errorf("eviction-mode must be either %s or %s", cache.EvictionModeDelete, cache.EvictionModeEviction)
+	}
+
+	if _, err := s.PriorityClassGracePeriods(); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// PriorityClassGracePeriods returns the grace period in seconds of evicted pods by PriorityClass name.
+func (s *ServerOption) PriorityClassGracePeriods() (map[string]int64, error) {
+	gracePeriods := map[string]int64{}
+	for name, value := range s.EvictionGracePeriods {
+		gracePeriod, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || gracePeriod < 0 {
+			return nil, fmt.Errorf("invalid grace period %q of PriorityClass %s in eviction-priority-class-grace-periods",
+				value, name)
+		}
+		gracePeriods[name] = gracePeriod
+	}
+
+	return gracePeriods, nil
+}
@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
 )
 
 func TestAddFlags(t *testing.T) {
@@ -40,6 +42,15 @@ func TestAddFlags(t *testing.T) {
 		SchedulePeriod: 5 * time.Minute,
 		DefaultQueue:   defaultQueue,
 		ListenAddress:  defaultListenAddress,
+
+		BindParallelism: defaultBindParallelism,
+		BindQPS:         defaultBindQPS,
+		BindBurst:       defaultBindBurst,
+		BindTimeout:     defaultBindTimeout,
+		AssumedTaskTTL:  defaultAssumedTaskTTL,
+
+		EvictionMode:        cache.EvictionModeDelete,
+		EvictionGracePeriod: defaultEvictionGracePeriod,
 	}
 
 	if !reflect.DeepEqual(expected, s) {
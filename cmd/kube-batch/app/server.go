@@ -26,6 +26,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/kubernetes-sigs/kube-batch/cmd/kube-batch/app/options"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler"
+	schedcache "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
 	"github.com/kubernetes-sigs/kube-batch/pkg/version"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
@@ -62,19 +63,44 @@ func Run(opt *options.ServerOption) error {
 		version.PrintVersionAndExit(apiVersion)
 	}
 
+	if len(opt.ValidateConf) != 0 {
+		if err := scheduler.ValidateSchedulerConf(opt.ValidateConf); err != nil {
+			return err
+		}
+		fmt.Printf("Scheduler configuration %s is valid\n", opt.ValidateConf)
+		os.Exit(0)
+	}
+
 	config, err := buildConfig(opt.Master, opt.Kubeconfig)
 	if err != nil {
 		return err
 	}
 
+	gracePeriods, err := opt.PriorityClassGracePeriods()
+	if err != nil {
+		return err
+	}
+
 	// Start policy controller to allocate resources.
 	sched, err := scheduler.NewScheduler(config,
 		opt.SchedulerName,
 		opt.SchedulerConf,
 		opt.SchedulePeriod,
-		opt.DefaultQueue)
+		opt.DefaultQueue,
+		schedcache.BindOptions{
+			Parallelism: opt.BindParallelism,
+			QPS:         opt.BindQPS,
+			Burst:       opt.BindBurst,
+			Timeout:     opt.BindTimeout,
+			AssumedTTL:  opt.AssumedTaskTTL,
+		},
+		schedcache.EvictOptions{
+			Mode:                            opt.EvictionMode,
+			GracePeriodSeconds:              opt.EvictionGracePeriod,
+			PriorityClassGracePeriodSeconds: gracePeriods,
+		})
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	go func() {
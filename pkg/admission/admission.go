@@ -0,0 +1,302 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	kbver "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
+)
+
+const (
+	// MutatePodGroupPath is the path of the PodGroup mutating webhook.
+	MutatePodGroupPath = "/podgroups/mutate"
+	// ValidatePodGroupPath is the path of the PodGroup validating webhook.
+	ValidatePodGroupPath = "/podgroups/validate"
+	// ValidateQueuePath is the path of the Queue validating webhook.
+	ValidateQueuePath = "/queues/validate"
+	// ValidatePodPath is the path of the Pod validating webhook.
+	ValidatePodPath = "/pods/validate"
+)
+
+// admitFunc admits the request of resource; the objects of v1alpha1 and v1alpha2 are
+// both decoded as v1alpha1, as their specs are the same.
+type admitFunc func(req *AdmissionRequest) *AdmissionResponse
+
+// Server serves admission reviews for kube-batch resources.
+type Server struct {
+	kbclient     kbver.Interface
+	defaultQueue string
+}
+
+// NewServer returns an admission Server using kbclient to look up queues and PodGroups.
+func NewServer(kbclient kbver.Interface, defaultQueue string) *Server {
+	return &Server{
+		kbclient:     kbclient,
+		defaultQueue: defaultQueue,
+	}
+}
+
+// Handler returns the http.Handler serving all admission webhooks.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(MutatePodGroupPath, s.serve(s.mutatePodGroup))
+	mux.HandleFunc(ValidatePodGroupPath, s.serve(s.validatePodGroup))
+	mux.HandleFunc(ValidateQueuePath, s.serve(s.validateQueue))
+	mux.HandleFunc(ValidatePodPath, s.serve(s.validatePod))
+	return mux
+}
+
+func (s *Server) serve(admit admitFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		review := &AdmissionReview{}
+		if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+			glog.Errorf("Failed to decode admission review: %v", err)
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+
+		resp := admit(review.Request)
+		resp.UID = review.Request.UID
+
+		out, err := json.Marshal(&AdmissionReview{
+			TypeMeta: review.TypeMeta,
+			Response: resp,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(out); err != nil {
+			glog.Errorf("Failed to write admission response: %v", err)
+		}
+	}
+}
+
+func allow() *AdmissionResponse {
+	return &AdmissionResponse{Allowed: true}
+}
+
+func deny(format string, args ...interface{}) *AdmissionResponse {
+	return &AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: fmt.Sprintf(format, args...),
+		},
+	}
+}
+
+// mutatePodGroup sets the queue of PodGroup to the default queue if not specified.
+func (s *Server) mutatePodGroup(req *AdmissionRequest) *AdmissionResponse {
+	if req.Operation != Create {
+		return allow()
+	}
+
+	pg := &kbv1.PodGroup{}
+	if err := json.Unmarshal(req.Object.Raw, pg); err != nil {
+		return deny("failed to decode PodGroup: %v", err)
+	}
+
+	if len(pg.Spec.Queue) != 0 {
+		return allow()
+	}
+
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "add", "path": "/spec/queue", "value": s.defaultQueue},
+	})
+	if err != nil {
+		return deny("failed to create patch: %v", err)
+	}
+
+	pt := PatchTypeJSONPatch
+	return &AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &pt,
+	}
+}
+
+// validatePodGroup validates the spec of PodGroup on creation, and on update if the spec
+// is changed; the updates of status by scheduler in every session are not validated.
+func (s *Server) validatePodGroup(req *AdmissionRequest) *AdmissionResponse {
+	if req.Operation != Create && req.Operation != Update {
+		return allow()
+	}
+
+	pg := &kbv1.PodGroup{}
+	if err := json.Unmarshal(req.Object.Raw, pg); err != nil {
+		return deny("failed to decode PodGroup: %v", err)
+	}
+
+	if req.Operation == Update {
+		old := &kbv1.PodGroup{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return deny("failed to decode old PodGroup: %v", err)
+		}
+		if reflect.DeepEqual(old.Spec, pg.Spec) {
+			return allow()
+		}
+	}
+
+	if pg.Spec.MinMember <= 0 {
+		return deny("minMember of PodGroup <%s/%s> must be greater than 0, got %d",
+			req.Namespace, pg.Name, pg.Spec.MinMember)
+	}
+
+	if pg.Spec.ScheduleTimeoutSeconds != nil && *pg.Spec.ScheduleTimeoutSeconds <= 0 {
+		return deny("scheduleTimeoutSeconds of PodGroup <%s/%s> must be greater than 0",
+			req.Namespace, pg.Name)
+	}
+
+	queue := pg.Spec.Queue
+	if len(queue) == 0 {
+		queue = s.defaultQueue
+	}
+	if _, err := s.kbclient.SchedulingV1alpha1().Queues().Get(queue, metav1.GetOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return deny("queue <%s> of PodGroup <%s/%s> does not exist", queue, req.Namespace, pg.Name)
+		}
+		return deny("failed to get queue <%s>: %v", queue, err)
+	}
+
+	return allow()
+}
+
+func (s *Server) validateQueue(req *AdmissionRequest) *AdmissionResponse {
+	switch req.Operation {
+	case Create, Update:
+		queue := &kbv1.Queue{}
+		if err := json.Unmarshal(req.Object.Raw, queue); err != nil {
+			return deny("failed to decode Queue: %v", err)
+		}
+		return s.validateQueueSpec(queue)
+	case Delete:
+		return s.validateQueueDeletion(req.Name)
+	}
+
+	return allow()
+}
+
+func (s *Server) validateQueueSpec(queue *kbv1.Queue) *AdmissionResponse {
+	if queue.Spec.Weight < 0 {
+		return deny("weight of queue <%s> must not be negative, got %d", queue.Name, queue.Spec.Weight)
+	}
+
+	switch queue.Spec.State {
+	case "", kbv1.QueueStateOpen, kbv1.QueueStateClosed:
+	default:
+		return deny("state of queue <%s> must be %s or %s, got %s",
+			queue.Name, kbv1.QueueStateOpen, kbv1.QueueStateClosed, queue.Spec.State)
+	}
+
+	if len(queue.Spec.Parent) != 0 {
+		if queue.Spec.Parent == queue.Name {
+			return deny("queue <%s> can not be the parent of itself", queue.Name)
+		}
+		if _, err := s.kbclient.SchedulingV1alpha1().Queues().Get(queue.Spec.Parent, metav1.GetOptions{}); err != nil {
+			if errors.IsNotFound(err) {
+				return deny("parent <%s> of queue <%s> does not exist", queue.Spec.Parent, queue.Name)
+			}
+			return deny("failed to get queue <%s>: %v", queue.Spec.Parent, err)
+		}
+	}
+
+	return allow()
+}
+
+// validateQueueDeletion rejects deleting a queue which still has PodGroups.
+func (s *Server) validateQueueDeletion(name string) *AdmissionResponse {
+	pgs, err := s.kbclient.SchedulingV1alpha1().PodGroups(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return deny("failed to list PodGroups: %v", err)
+	}
+
+	for _, pg := range pgs.Items {
+		queue := pg.Spec.Queue
+		if len(queue) == 0 {
+			queue = s.defaultQueue
+		}
+		if queue == name {
+			return deny("queue <%s> can not be deleted, it still has PodGroup <%s/%s>",
+				name, pg.Namespace, pg.Name)
+		}
+	}
+
+	return allow()
+}
+
+// validatePod rejects pods whose PodGroup annotation refers to a PodGroup which does not
+// exist in the namespace of Pod.
+func (s *Server) validatePod(req *AdmissionRequest) *AdmissionResponse {
+	if req.Operation != Create {
+		return allow()
+	}
+
+	pod := &v1.Pod{}
+	if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+		return deny("failed to decode Pod: %v", err)
+	}
+
+	groupName, found := pod.Annotations[kbv1.GroupNameAnnotationKey]
+	if !found {
+		return allow()
+	}
+
+	namespace := pod.Namespace
+	if len(namespace) == 0 {
+		namespace = req.Namespace
+	}
+
+	// PodGroup is looked up in the namespace of Pod, so a qualified name
+	// always refers to a PodGroup in another namespace.
+	if strings.Contains(groupName, "/") {
+		return deny("PodGroup <%s> of Pod <%s/%s> must be in namespace <%s>",
+			groupName, namespace, pod.Name, namespace)
+	}
+
+	if _, err := s.kbclient.SchedulingV1alpha1().PodGroups(namespace).Get(groupName, metav1.GetOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return deny("PodGroup <%s> of Pod <%s/%s> does not exist in namespace <%s>",
+				groupName, namespace, pod.Name, namespace)
+		}
+		return deny("failed to get PodGroup <%s/%s>: %v", namespace, groupName, err)
+	}
+
+	return allow()
+}
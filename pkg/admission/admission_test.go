@@ -0,0 +1,274 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/fake"
+)
+
+func buildQueue(name, parent string) *kbv1.Queue {
+	return &kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: kbv1.QueueSpec{
+			Weight: 1,
+			Parent: parent,
+		},
+	}
+}
+
+func buildPodGroup(ns, name, queue string, minMember int32) *kbv1.PodGroup {
+	return &kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: kbv1.PodGroupSpec{
+			MinMember: minMember,
+			Queue:     queue,
+		},
+	}
+}
+
+func buildPod(ns, name, group string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   ns,
+			Name:        name,
+			Annotations: map[string]string{kbv1.GroupNameAnnotationKey: group},
+		},
+	}
+}
+
+func review(t *testing.T, url string, req *AdmissionRequest) *AdmissionResponse {
+	body, err := json.Marshal(&AdmissionReview{Request: req})
+	if err != nil {
+		t.Fatalf("failed to encode review: %v", err)
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to post review: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d", resp.StatusCode)
+	}
+
+	out := &AdmissionReview{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("failed to decode review: %v", err)
+	}
+	if out.Response == nil {
+		t.Fatalf("no response in review")
+	}
+	if out.Response.UID != req.UID {
+		t.Errorf("expected UID %s, got %s", req.UID, out.Response.UID)
+	}
+
+	return out.Response
+}
+
+func raw(t *testing.T, obj interface{}) runtime.RawExtension {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to encode object: %v", err)
+	}
+	return runtime.RawExtension{Raw: data}
+}
+
+func TestAdmission(t *testing.T) {
+	kbclient := fake.NewSimpleClientset(
+		buildQueue("default", ""),
+		buildQueue("q1", ""),
+		buildQueue("q2", ""),
+		buildPodGroup("c1", "pg1", "q1", 1),
+		buildPodGroup("c1", "pg2", "", 1),
+	)
+
+	server := httptest.NewServer(NewServer(kbclient, "default").Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		request *AdmissionRequest
+		allowed bool
+		patched bool
+	}{
+		{
+			name: "default queue of PodGroup",
+			path: MutatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPodGroup("c1", "pg3", "", 1)),
+			},
+			allowed: true,
+			patched: true,
+		},
+		{
+			name: "keep queue of PodGroup",
+			path: MutatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPodGroup("c1", "pg3", "q1", 1)),
+			},
+			allowed: true,
+		},
+		{
+			name: "valid PodGroup",
+			path: ValidatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPodGroup("c1", "pg3", "q1", 2)),
+			},
+			allowed: true,
+		},
+		{
+			name: "PodGroup with zero minMember",
+			path: ValidatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPodGroup("c1", "pg3", "q1", 0)),
+			},
+			allowed: false,
+		},
+		{
+			name: "update of PodGroup without spec change not validated",
+			path: ValidatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Update,
+				Object:    raw(t, buildPodGroup("c1", "pg1", "q3", 1)),
+				OldObject: raw(t, buildPodGroup("c1", "pg1", "q3", 1)),
+			},
+			allowed: true,
+		},
+		{
+			name: "update of PodGroup spec validated",
+			path: ValidatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Update,
+				Object:    raw(t, buildPodGroup("c1", "pg1", "q1", 0)),
+				OldObject: raw(t, buildPodGroup("c1", "pg1", "q1", 1)),
+			},
+			allowed: false,
+		},
+		{
+			name: "valid update of PodGroup spec",
+			path: ValidatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Update,
+				Object:    raw(t, buildPodGroup("c1", "pg1", "q2", 2)),
+				OldObject: raw(t, buildPodGroup("c1", "pg1", "q1", 1)),
+			},
+			allowed: true,
+		},
+		{
+			name: "PodGroup with unknown queue",
+			path: ValidatePodGroupPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPodGroup("c1", "pg3", "q3", 1)),
+			},
+			allowed: false,
+		},
+		{
+			name: "Queue with unknown parent",
+			path: ValidateQueuePath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildQueue("q3", "q4")),
+			},
+			allowed: false,
+		},
+		{
+			name: "delete Queue with PodGroups",
+			path: ValidateQueuePath,
+			request: &AdmissionRequest{
+				Operation: Delete,
+				Name:      "q1",
+			},
+			allowed: false,
+		},
+		{
+			name: "delete default Queue with PodGroups",
+			path: ValidateQueuePath,
+			request: &AdmissionRequest{
+				Operation: Delete,
+				Name:      "default",
+			},
+			allowed: false,
+		},
+		{
+			name: "delete empty Queue",
+			path: ValidateQueuePath,
+			request: &AdmissionRequest{
+				Operation: Delete,
+				Name:      "q2",
+			},
+			allowed: true,
+		},
+		{
+			name: "Pod in the namespace of PodGroup",
+			path: ValidatePodPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPod("c1", "p1", "pg1")),
+			},
+			allowed: true,
+		},
+		{
+			name: "Pod with PodGroup not in its namespace",
+			path: ValidatePodPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPod("c2", "p1", "pg1")),
+			},
+			allowed: false,
+		},
+		{
+			name: "Pod with PodGroup in another namespace",
+			path: ValidatePodPath,
+			request: &AdmissionRequest{
+				Operation: Create,
+				Object:    raw(t, buildPod("c2", "p1", "c1/pg1")),
+			},
+			allowed: false,
+		},
+	}
+
+	for i, test := range tests {
+		test.request.UID = "uid"
+		resp := review(t, server.URL+test.path, test.request)
+
+		if resp.Allowed != test.allowed {
+			t.Errorf("case %d (%s): expected allowed %v, got %v (%v)",
+				i, test.name, test.allowed, resp.Allowed, resp.Result)
+		}
+		if patched := len(resp.Patch) != 0; patched != test.patched {
+			t.Errorf("case %d (%s): expected patched %v, got %v", i, test.name, test.patched, patched)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The types below are the wire format of admission.k8s.io/v1beta1 AdmissionReview;
+// only the fields used by kube-batch are defined, as k8s.io/api/admission is not vendored.
+
+// AdmissionReview describes an admission review request/response.
+type AdmissionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// Request describes the attributes for the admission request.
+	Request *AdmissionRequest `json:"request,omitempty"`
+	// Response describes the attributes for the admission response.
+	Response *AdmissionResponse `json:"response,omitempty"`
+}
+
+// Operation is the type of resource operation being checked for admission control
+type Operation string
+
+// Operation constants
+const (
+	Create Operation = "CREATE"
+	Update Operation = "UPDATE"
+	Delete Operation = "DELETE"
+)
+
+// AdmissionRequest describes the admission.Attributes for the admission request.
+type AdmissionRequest struct {
+	// UID is an identifier for the individual request/response.
+	UID types.UID `json:"uid"`
+	// Kind is the type of object being manipulated.
+	Kind metav1.GroupVersionKind `json:"kind"`
+	// Resource is the name of the resource being requested.
+	Resource metav1.GroupVersionResource `json:"resource"`
+	// Name is the name of the object as presented in the request.
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace associated with the request (if any).
+	Namespace string `json:"namespace,omitempty"`
+	// Operation is the operation being performed.
+	Operation Operation `json:"operation"`
+	// Object is the object from the incoming request prior to default values being applied.
+	Object runtime.RawExtension `json:"object,omitempty"`
+	// OldObject is the existing object. Only populated for UPDATE requests.
+	OldObject runtime.RawExtension `json:"oldObject,omitempty"`
+}
+
+// PatchType is the type of patch being used to represent the mutated object.
+type PatchType string
+
+// PatchType constants.
+const (
+	PatchTypeJSONPatch PatchType = "JSONPatch"
+)
+
+// AdmissionResponse describes an admission response.
+type AdmissionResponse struct {
+	// UID is an identifier for the individual request/response.
+	UID types.UID `json:"uid"`
+	// Allowed indicates whether or not the admission request was permitted.
+	Allowed bool `json:"allowed"`
+	// Result contains extra details into why an admission request was denied.
+	Result *metav1.Status `json:"status,omitempty"`
+	// The patch body, only JSONPatch is supported.
+	Patch []byte `json:"patch,omitempty"`
+	// The type of Patch.
+	PatchType *PatchType `json:"patchType,omitempty"`
+}
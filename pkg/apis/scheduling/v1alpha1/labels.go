@@ -19,3 +19,18 @@ package v1alpha1
 // GroupNameAnnotationKey is the annotation key of Pod to identify
 // which PodGroup it belongs to.
 const GroupNameAnnotationKey = "scheduling.k8s.io/group-name"
+
+// PodOverheadAnnotationKey is the annotation key of Pod for the resources
+// used by the pod sandbox, e.g. the VM of Kata Containers, in JSON format,
+// e.g. `{"cpu": "250m", "memory": "120Mi"}`; they're accounted in addition
+// to the requests of containers.
+const PodOverheadAnnotationKey = "scheduling.k8s.io/pod-overhead"
+
+// ExcludedContainersAnnotationKey is the annotation key of Pod for the
+// comma-separated names of containers whose requests are not accounted.
+const ExcludedContainersAnnotationKey = "scheduling.k8s.io/excluded-containers"
+
+// SidecarInitContainersAnnotationKey is the annotation key of Pod for the
+// comma-separated names of init containers which keep running as sidecars
+// after started; their requests are accounted together with containers.
+const SidecarInitContainersAnnotationKey = "scheduling.k8s.io/sidecar-init-containers"
@@ -30,12 +30,21 @@ const (
 	// enough resources to it.
 	PodGroupPending PodGroupPhase = "Pending"
 
+	// PodGroupInqueue means the pod group has been admitted by scheduler, and
+	// scheduler will try to allocate resources to it; controllers can create
+	// pods of the pod group after it's admitted.
+	PodGroupInqueue PodGroupPhase = "Inqueue"
+
 	// PodRunning means `spec.minMember` pods of PodGroups has been in running phase.
 	PodGroupRunning PodGroupPhase = "Running"
 
 	// PodGroupUnknown means part of `spec.minMember` pods are running but the other part can not
 	// be scheduled, e.g. not enough resource; scheduler will wait for related controller to recover it.
 	PodGroupUnknown PodGroupPhase = "Unknown"
+
+	// PodGroupFailed means the pod group has been unschedulable for longer than
+	// `spec.scheduleTimeoutSeconds`; scheduler will not schedule it anymore.
+	PodGroupFailed PodGroupPhase = "Failed"
 )
 
 type PodGroupConditionType string
@@ -80,6 +89,21 @@ const (
 
 	// NotEnoughPodsReason is probed if there're not enough tasks compared to `spec.minMember`
 	NotEnoughPodsReason string = "NotEnoughTasks"
+
+	// QueueClosedReason is probed if the queue of PodGroup is not open for new PodGroups
+	QueueClosedReason string = "QueueClosed"
+
+	// ExceedClusterResourcesReason is probed if `spec.minResources` of PodGroup exceeds
+	// the total allocatable resources of the cluster
+	ExceedClusterResourcesReason string = "ExceedClusterResources"
+
+	// ExceedQueueResourcesReason is probed if `spec.minResources` of PodGroup exceeds
+	// the deserved resources of its queue
+	ExceedQueueResourcesReason string = "ExceedQueueResources"
+
+	// ScheduleTimeoutReason is probed if PodGroup has been unschedulable for longer
+	// than `spec.scheduleTimeoutSeconds`
+	ScheduleTimeoutReason string = "ScheduleTimeout"
 )
 
 // +genclient
@@ -123,6 +147,18 @@ type PodGroupSpec struct {
 	// default.
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty" protobuf:"bytes,3,opt,name=priorityClassName"`
+
+	// MinResources defines the minimal resources of members/tasks to run the pod group;
+	// if there's not enough resources in the queue or cluster, the scheduler
+	// will not try to start any of them.
+	// +optional
+	MinResources v1.ResourceList `json:"minResources,omitempty" protobuf:"bytes,4,opt,name=minResources"`
+
+	// ScheduleTimeoutSeconds defines how long the pod group can be unschedulable;
+	// if it's unschedulable for longer than that, its phase becomes Failed and
+	// the scheduler will not schedule it anymore. It never times out if not specified.
+	// +optional
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty" protobuf:"bytes,5,opt,name=scheduleTimeoutSeconds"`
 }
 
 // PodGroupStatus represents the current state of a pod group.
@@ -145,6 +181,22 @@ type PodGroupStatus struct {
 	// The number of pods which reached phase Failed.
 	// +optional
 	Failed int32 `json:"failed,omitempty" protobuf:"bytes,5,opt,name=failed"`
+
+	// The number of pods which are waiting for resources.
+	// +optional
+	Pending int32 `json:"pending,omitempty" protobuf:"bytes,6,opt,name=pending"`
+
+	// The resources allocated to the pods of PodGroup.
+	// +optional
+	Allocated v1.ResourceList `json:"allocated,omitempty" protobuf:"bytes,7,opt,name=allocated"`
+
+	// The last time `spec.minMember` pods of PodGroup were allocated resources.
+	// +optional
+	MinAvailableSatisfiedTime *metav1.Time `json:"minAvailableSatisfiedTime,omitempty" protobuf:"bytes,8,opt,name=minAvailableSatisfiedTime"`
+
+	// The ID of the last scheduling session which updated the PodGroup.
+	// +optional
+	SessionID string `json:"sessionID,omitempty" protobuf:"bytes,9,opt,name=sessionID"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -177,11 +229,71 @@ type Queue struct {
 	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#spec-and-status
 	// +optional
 	Spec QueueSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+
+	// The status of queue.
+	// +optional
+	Status QueueStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
 }
 
+// QueueState is the state of a queue.
+type QueueState string
+
+// These are the valid states of queues.
+const (
+	// QueueStateOpen means new PodGroups in the queue will be scheduled.
+	QueueStateOpen QueueState = "Open"
+
+	// QueueStateClosed means new PodGroups in the queue will not be scheduled,
+	// and there's no running PodGroup in the queue.
+	QueueStateClosed QueueState = "Closed"
+
+	// QueueStateClosing means new PodGroups in the queue will not be scheduled,
+	// but there're still running PodGroups in the queue.
+	QueueStateClosing QueueState = "Closing"
+)
+
 // QueueSpec represents the template of Queue.
 type QueueSpec struct {
 	Weight int32 `json:"weight,omitempty" protobuf:"bytes,1,opt,name=weight"`
+
+	// Capability defines the upper limit of resources the Queue can use;
+	// resources not listed here are not limited.
+	// +optional
+	Capability v1.ResourceList `json:"capability,omitempty" protobuf:"bytes,2,opt,name=capability"`
+
+	// State is the desired state of queue, either Open or Closed; the default
+	// is Open. New PodGroups in a Closed queue will not be scheduled, but the
+	// running PodGroups will keep running until they finish.
+	// +optional
+	State QueueState `json:"state,omitempty" protobuf:"bytes,3,opt,name=state"`
+
+	// Parent is the name of the parent queue; the resources deserved by the
+	// parent queue are divided among its child queues by their weights.
+	// The queue is a root queue if it's empty.
+	// +optional
+	Parent string `json:"parent,omitempty" protobuf:"bytes,4,opt,name=parent"`
+}
+
+// QueueStatus represents the status of Queue.
+type QueueStatus struct {
+	// State is the current state of queue.
+	State QueueState `json:"state,omitempty" protobuf:"bytes,1,opt,name=state"`
+
+	// The number of 'Pending' PodGroups in this queue.
+	// +optional
+	Pending int32 `json:"pending,omitempty" protobuf:"bytes,2,opt,name=pending"`
+
+	// The number of 'Running' PodGroups in this queue.
+	// +optional
+	Running int32 `json:"running,omitempty" protobuf:"bytes,3,opt,name=running"`
+
+	// The number of 'Unknown' PodGroups in this queue.
+	// +optional
+	Unknown int32 `json:"unknown,omitempty" protobuf:"bytes,4,opt,name=unknown"`
+
+	// The number of 'Inqueue' PodGroups in this queue.
+	// +optional
+	Inqueue int32 `json:"inqueue,omitempty" protobuf:"bytes,5,opt,name=inqueue"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -197,3 +309,55 @@ type QueueList struct {
 	// items is the list of PodGroup
 	Items []Queue `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Reservation holds resources on nodes for a PodGroup, so the PodGroup will not
+// starve behind other PodGroups while waiting for enough resources.
+type Reservation struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the desired behavior of the reservation.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#spec-and-status
+	// +optional
+	Spec ReservationSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// ReservationSpec represents the template of Reservation.
+type ReservationSpec struct {
+	// PodGroup is the name of the PodGroup, in the namespace of Reservation,
+	// which owns the reserved resources.
+	PodGroup string `json:"podGroup" protobuf:"bytes,1,opt,name=podGroup"`
+
+	// Resources is the amount of resources to reserve.
+	Resources v1.ResourceList `json:"resources,omitempty" protobuf:"bytes,2,opt,name=resources"`
+
+	// NodeSelector selects the nodes on which resources are reserved; resources
+	// are reserved on any node if it's empty.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,3,rep,name=nodeSelector"`
+
+	// TTLSeconds is the lifetime of the reservation since it's created; the
+	// reservation never expires if it's not set.
+	// +optional
+	TTLSeconds *int32 `json:"ttlSeconds,omitempty" protobuf:"varint,4,opt,name=ttlSeconds"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReservationList is a collection of reservations.
+type ReservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// items is the list of Reservation
+	Items []Reservation `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
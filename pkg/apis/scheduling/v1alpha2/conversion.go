@@ -0,0 +1,176 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+)
+
+// RegisterConversions adds conversion functions between v1alpha1 and v1alpha2
+// to the given scheme. v1alpha1 is still the storage version, so the objects
+// of v1alpha2 are converted to v1alpha1 before used by scheduler.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddConversionFunc((*v1alpha1.PodGroup)(nil), (*PodGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PodGroup_To_v1alpha2_PodGroup(a.(*v1alpha1.PodGroup), b.(*PodGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*PodGroup)(nil), (*v1alpha1.PodGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PodGroup_To_v1alpha1_PodGroup(a.(*PodGroup), b.(*v1alpha1.PodGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha1.Queue)(nil), (*Queue)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Queue_To_v1alpha2_Queue(a.(*v1alpha1.Queue), b.(*Queue), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*Queue)(nil), (*v1alpha1.Queue)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_Queue_To_v1alpha1_Queue(a.(*Queue), b.(*v1alpha1.Queue), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_PodGroup_To_v1alpha2_PodGroup converts a v1alpha1 PodGroup to v1alpha2.
+func Convert_v1alpha1_PodGroup_To_v1alpha2_PodGroup(in *v1alpha1.PodGroup, out *PodGroup, s conversion.Scope) error {
+	out.TypeMeta = in.TypeMeta
+	out.TypeMeta.APIVersion = SchemeGroupVersion.String()
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec.MinMember = in.Spec.MinMember
+	out.Spec.Queue = in.Spec.Queue
+	out.Spec.PriorityClassName = in.Spec.PriorityClassName
+	out.Spec.MinResources = copyResourceList(in.Spec.MinResources)
+	if in.Spec.ScheduleTimeoutSeconds != nil {
+		timeout := *in.Spec.ScheduleTimeoutSeconds
+		out.Spec.ScheduleTimeoutSeconds = &timeout
+	}
+
+	out.Status.Phase = PodGroupPhase(in.Status.Phase)
+	out.Status.Conditions = nil
+	for _, c := range in.Status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, PodGroupCondition{
+			Type:               PodGroupConditionType(c.Type),
+			Status:             c.Status,
+			TransitionID:       c.TransitionID,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	out.Status.Running = in.Status.Running
+	out.Status.Succeeded = in.Status.Succeeded
+	out.Status.Failed = in.Status.Failed
+	out.Status.Pending = in.Status.Pending
+	out.Status.Allocated = copyResourceList(in.Status.Allocated)
+	out.Status.MinAvailableSatisfiedTime = in.Status.MinAvailableSatisfiedTime.DeepCopy()
+	out.Status.SessionID = in.Status.SessionID
+
+	return nil
+}
+
+// Convert_v1alpha2_PodGroup_To_v1alpha1_PodGroup converts a v1alpha2 PodGroup to v1alpha1.
+func Convert_v1alpha2_PodGroup_To_v1alpha1_PodGroup(in *PodGroup, out *v1alpha1.PodGroup, s conversion.Scope) error {
+	out.TypeMeta = in.TypeMeta
+	out.TypeMeta.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec.MinMember = in.Spec.MinMember
+	out.Spec.Queue = in.Spec.Queue
+	out.Spec.PriorityClassName = in.Spec.PriorityClassName
+	out.Spec.MinResources = copyResourceList(in.Spec.MinResources)
+	if in.Spec.ScheduleTimeoutSeconds != nil {
+		timeout := *in.Spec.ScheduleTimeoutSeconds
+		out.Spec.ScheduleTimeoutSeconds = &timeout
+	}
+
+	out.Status.Phase = v1alpha1.PodGroupPhase(in.Status.Phase)
+	out.Status.Conditions = nil
+	for _, c := range in.Status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, v1alpha1.PodGroupCondition{
+			Type:               v1alpha1.PodGroupConditionType(c.Type),
+			Status:             c.Status,
+			TransitionID:       c.TransitionID,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	out.Status.Running = in.Status.Running
+	out.Status.Succeeded = in.Status.Succeeded
+	out.Status.Failed = in.Status.Failed
+	out.Status.Pending = in.Status.Pending
+	out.Status.Allocated = copyResourceList(in.Status.Allocated)
+	out.Status.MinAvailableSatisfiedTime = in.Status.MinAvailableSatisfiedTime.DeepCopy()
+	out.Status.SessionID = in.Status.SessionID
+
+	return nil
+}
+
+// Convert_v1alpha1_Queue_To_v1alpha2_Queue converts a v1alpha1 Queue to v1alpha2.
+func Convert_v1alpha1_Queue_To_v1alpha2_Queue(in *v1alpha1.Queue, out *Queue, s conversion.Scope) error {
+	out.TypeMeta = in.TypeMeta
+	out.TypeMeta.APIVersion = SchemeGroupVersion.String()
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec.Weight = in.Spec.Weight
+	out.Spec.Capability = copyResourceList(in.Spec.Capability)
+	out.Spec.State = QueueState(in.Spec.State)
+	out.Spec.Parent = in.Spec.Parent
+
+	out.Status.State = QueueState(in.Status.State)
+	out.Status.Pending = in.Status.Pending
+	out.Status.Running = in.Status.Running
+	out.Status.Unknown = in.Status.Unknown
+	out.Status.Inqueue = in.Status.Inqueue
+
+	return nil
+}
+
+// Convert_v1alpha2_Queue_To_v1alpha1_Queue converts a v1alpha2 Queue to v1alpha1.
+func Convert_v1alpha2_Queue_To_v1alpha1_Queue(in *Queue, out *v1alpha1.Queue, s conversion.Scope) error {
+	out.TypeMeta = in.TypeMeta
+	out.TypeMeta.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec.Weight = in.Spec.Weight
+	out.Spec.Capability = copyResourceList(in.Spec.Capability)
+	out.Spec.State = v1alpha1.QueueState(in.Spec.State)
+	out.Spec.Parent = in.Spec.Parent
+
+	out.Status.State = v1alpha1.QueueState(in.Status.State)
+	out.Status.Pending = in.Status.Pending
+	out.Status.Running = in.Status.Running
+	out.Status.Unknown = in.Status.Unknown
+	out.Status.Inqueue = in.Status.Inqueue
+
+	return nil
+}
+
+func copyResourceList(rl v1.ResourceList) v1.ResourceList {
+	if rl == nil {
+		return nil
+	}
+
+	return rl.DeepCopy()
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestPodGroupConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add v1alpha2 to scheme: %v", err)
+	}
+
+	timeout := int32(60)
+	now := metav1.Now()
+	in := &v1alpha1.PodGroup{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "PodGroup",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "c1",
+			Name:      "pg1",
+		},
+		Spec: v1alpha1.PodGroupSpec{
+			MinMember: 2,
+			Queue:     "q1",
+			MinResources: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("2"),
+			},
+			ScheduleTimeoutSeconds: &timeout,
+		},
+		Status: v1alpha1.PodGroupStatus{
+			Phase: v1alpha1.PodGroupInqueue,
+			Conditions: []v1alpha1.PodGroupCondition{
+				{
+					Type:               v1alpha1.PodGroupUnschedulableType,
+					Status:             v1.ConditionTrue,
+					LastTransitionTime: now,
+					Reason:             v1alpha1.NotEnoughResourcesReason,
+				},
+			},
+			Pending:                   2,
+			MinAvailableSatisfiedTime: &now,
+		},
+	}
+
+	pg := &PodGroup{}
+	if err := scheme.Convert(in, pg, nil); err != nil {
+		t.Fatalf("Failed to convert PodGroup to v1alpha2: %v", err)
+	}
+	if pg.APIVersion != SchemeGroupVersion.String() {
+		t.Errorf("expected apiVersion %s, got %s", SchemeGroupVersion, pg.APIVersion)
+	}
+
+	out := &v1alpha1.PodGroup{}
+	if err := scheme.Convert(pg, out, nil); err != nil {
+		t.Fatalf("Failed to convert PodGroup to v1alpha1: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %v, got %v", in, out)
+	}
+}
+
+func TestQueueConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add v1alpha2 to scheme: %v", err)
+	}
+
+	in := &v1alpha1.Queue{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Queue",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "q1",
+		},
+		Spec: v1alpha1.QueueSpec{
+			Weight: 2,
+			Capability: v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			State:  v1alpha1.QueueStateClosed,
+			Parent: "root",
+		},
+		Status: v1alpha1.QueueStatus{
+			State:   v1alpha1.QueueStateClosing,
+			Running: 1,
+		},
+	}
+
+	queue := &Queue{}
+	if err := scheme.Convert(in, queue, nil); err != nil {
+		t.Fatalf("Failed to convert Queue to v1alpha2: %v", err)
+	}
+
+	out := &v1alpha1.Queue{}
+	if err := scheme.Convert(queue, out, nil); err != nil {
+		t.Fatalf("Failed to convert Queue to v1alpha1: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %v, got %v", in, out)
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodGroupPhase is the phase of a pod group at the current time.
+type PodGroupPhase string
+
+// These are the valid phase of podGroups.
+const (
+	// PodPending means the pod group has been accepted by the system, but scheduler can not allocate
+	// enough resources to it.
+	PodGroupPending PodGroupPhase = "Pending"
+
+	// PodGroupInqueue means the pod group has been admitted by scheduler, and
+	// scheduler will try to allocate resources to it; controllers can create
+	// pods of the pod group after it's admitted.
+	PodGroupInqueue PodGroupPhase = "Inqueue"
+
+	// PodRunning means `spec.minMember` pods of PodGroups has been in running phase.
+	PodGroupRunning PodGroupPhase = "Running"
+
+	// PodGroupUnknown means part of `spec.minMember` pods are running but the other part can not
+	// be scheduled, e.g. not enough resource; scheduler will wait for related controller to recover it.
+	PodGroupUnknown PodGroupPhase = "Unknown"
+
+	// PodGroupFailed means the pod group has been unschedulable for longer than
+	// `spec.scheduleTimeoutSeconds`; scheduler will not schedule it anymore.
+	PodGroupFailed PodGroupPhase = "Failed"
+)
+
+type PodGroupConditionType string
+
+const (
+	PodGroupUnschedulableType PodGroupConditionType = "Unschedulable"
+)
+
+// PodGroupCondition contains details for the current state of this pod group.
+type PodGroupCondition struct {
+	// Type is the type of the condition
+	Type PodGroupConditionType `json:"type,omitempty" protobuf:"bytes,1,opt,name=type"`
+
+	// Status is the status of the condition.
+	Status v1.ConditionStatus `json:"status,omitempty" protobuf:"bytes,2,opt,name=status"`
+
+	// The ID of condition transition.
+	TransitionID string `json:"transitionID,omitempty" protobuf:"bytes,3,opt,name=transitionID"`
+
+	// Last time the phase transitioned from another to current phase.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,4,opt,name=lastTransitionTime"`
+
+	// Unique, one-word, CamelCase reason for the phase's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
+
+	// Human-readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
+}
+
+const (
+	// PodFailedReason is probed if pod of PodGroup failed
+	PodFailedReason string = "PodFailed"
+
+	// PodDeletedReason is probed if pod of PodGroup deleted
+	PodDeletedReason string = "PodDeleted"
+
+	// NotEnoughResourcesReason is probed if there're not enough resources to schedule pods
+	NotEnoughResourcesReason string = "NotEnoughResources"
+
+	// NotEnoughPodsReason is probed if there're not enough tasks compared to `spec.minMember`
+	NotEnoughPodsReason string = "NotEnoughTasks"
+
+	// QueueClosedReason is probed if the queue of PodGroup is not open for new PodGroups
+	QueueClosedReason string = "QueueClosed"
+
+	// ExceedClusterResourcesReason is probed if `spec.minResources` of PodGroup exceeds
+	// the total allocatable resources of the cluster
+	ExceedClusterResourcesReason string = "ExceedClusterResources"
+
+	// ExceedQueueResourcesReason is probed if `spec.minResources` of PodGroup exceeds
+	// the deserved resources of its queue
+	ExceedQueueResourcesReason string = "ExceedQueueResources"
+
+	// ScheduleTimeoutReason is probed if PodGroup has been unschedulable for longer
+	// than `spec.scheduleTimeoutSeconds`
+	ScheduleTimeoutReason string = "ScheduleTimeout"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PodGroup is a collection of Pod; used for batch workload.
+type PodGroup struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the desired behavior of the pod group.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#spec-and-status
+	// +optional
+	Spec PodGroupSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+
+	// Status represents the current information about a pod group.
+	// This data may not be up to date.
+	// +optional
+	Status PodGroupStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// PodGroupSpec represents the template of a pod group.
+type PodGroupSpec struct {
+	// MinMember defines the minimal number of members/tasks to run the pod group;
+	// if there's not enough resources to start all tasks, the scheduler
+	// will not start anyone.
+	MinMember int32 `json:"minMember,omitempty" protobuf:"bytes,1,opt,name=minMember"`
+
+	// Queue defines the queue to allocate resource for PodGroup; if queue does not exist,
+	// the PodGroup will not be scheduled.
+	Queue string `json:"queue,omitempty" protobuf:"bytes,2,opt,name=queue"`
+
+	// If specified, indicates the PodGroup's priority. "system-node-critical" and
+	// "system-cluster-critical" are two special keywords which indicate the
+	// highest priorities with the former being the highest priority. Any other
+	// name must be defined by creating a PriorityClass object with that name.
+	// If not specified, the PodGroup priority will be default or zero if there is no
+	// default.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty" protobuf:"bytes,3,opt,name=priorityClassName"`
+
+	// MinResources defines the minimal resources of members/tasks to run the pod group;
+	// if there's not enough resources in the queue or cluster, the scheduler
+	// will not try to start any of them.
+	// +optional
+	MinResources v1.ResourceList `json:"minResources,omitempty" protobuf:"bytes,4,opt,name=minResources"`
+
+	// ScheduleTimeoutSeconds defines how long the pod group can be unschedulable;
+	// if it's unschedulable for longer than that, its phase becomes Failed and
+	// the scheduler will not schedule it anymore. It never times out if not specified.
+	// +optional
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty" protobuf:"bytes,5,opt,name=scheduleTimeoutSeconds"`
+}
+
+// PodGroupStatus represents the current state of a pod group.
+type PodGroupStatus struct {
+	// Current phase of PodGroup.
+	Phase PodGroupPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase"`
+
+	// The conditions of PodGroup.
+	// +optional
+	Conditions []PodGroupCondition `json:"conditions,omitempty" protobuf:"bytes,2,opt,name=conditions"`
+
+	// The number of actively running pods.
+	// +optional
+	Running int32 `json:"running,omitempty" protobuf:"bytes,3,opt,name=running"`
+
+	// The number of pods which reached phase Succeeded.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty" protobuf:"bytes,4,opt,name=succeeded"`
+
+	// The number of pods which reached phase Failed.
+	// +optional
+	Failed int32 `json:"failed,omitempty" protobuf:"bytes,5,opt,name=failed"`
+
+	// The number of pods which are waiting for resources.
+	// +optional
+	Pending int32 `json:"pending,omitempty" protobuf:"bytes,6,opt,name=pending"`
+
+	// The resources allocated to the pods of PodGroup.
+	// +optional
+	Allocated v1.ResourceList `json:"allocated,omitempty" protobuf:"bytes,7,opt,name=allocated"`
+
+	// The last time `spec.minMember` pods of PodGroup were allocated resources.
+	// +optional
+	MinAvailableSatisfiedTime *metav1.Time `json:"minAvailableSatisfiedTime,omitempty" protobuf:"bytes,8,opt,name=minAvailableSatisfiedTime"`
+
+	// The ID of the last scheduling session which updated the PodGroup.
+	// +optional
+	SessionID string `json:"sessionID,omitempty" protobuf:"bytes,9,opt,name=sessionID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PodGroupList is a collection of pod groups.
+type PodGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// items is the list of PodGroup
+	Items []PodGroup `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Queue is a queue of PodGroup.
+type Queue struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the desired behavior of the pod group.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#spec-and-status
+	// +optional
+	Spec QueueSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+
+	// The status of queue.
+	// +optional
+	Status QueueStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// QueueState is the state of a queue.
+type QueueState string
+
+// These are the valid states of queues.
+const (
+	// QueueStateOpen means new PodGroups in the queue will be scheduled.
+	QueueStateOpen QueueState = "Open"
+
+	// QueueStateClosed means new PodGroups in the queue will not be scheduled,
+	// and there's no running PodGroup in the queue.
+	QueueStateClosed QueueState = "Closed"
+
+	// QueueStateClosing means new PodGroups in the queue will not be scheduled,
+	// but there're still running PodGroups in the queue.
+	QueueStateClosing QueueState = "Closing"
+)
+
+// QueueSpec represents the template of Queue.
+type QueueSpec struct {
+	Weight int32 `json:"weight,omitempty" protobuf:"bytes,1,opt,name=weight"`
+
+	// Capability defines the upper limit of resources the Queue can use;
+	// resources not listed here are not limited.
+	// +optional
+	Capability v1.ResourceList `json:"capability,omitempty" protobuf:"bytes,2,opt,name=capability"`
+
+	// State is the desired state of queue, either Open or Closed; the default
+	// is Open. New PodGroups in a Closed queue will not be scheduled, but the
+	// running PodGroups will keep running until they finish.
+	// +optional
+	State QueueState `json:"state,omitempty" protobuf:"bytes,3,opt,name=state"`
+
+	// Parent is the name of the parent queue; the resources deserved by the
+	// parent queue are divided among its child queues by their weights.
+	// The queue is a root queue if it's empty.
+	// +optional
+	Parent string `json:"parent,omitempty" protobuf:"bytes,4,opt,name=parent"`
+}
+
+// QueueStatus represents the status of Queue.
+type QueueStatus struct {
+	// State is the current state of queue.
+	State QueueState `json:"state,omitempty" protobuf:"bytes,1,opt,name=state"`
+
+	// The number of 'Pending' PodGroups in this queue.
+	// +optional
+	Pending int32 `json:"pending,omitempty" protobuf:"bytes,2,opt,name=pending"`
+
+	// The number of 'Running' PodGroups in this queue.
+	// +optional
+	Running int32 `json:"running,omitempty" protobuf:"bytes,3,opt,name=running"`
+
+	// The number of 'Unknown' PodGroups in this queue.
+	// +optional
+	Unknown int32 `json:"unknown,omitempty" protobuf:"bytes,4,opt,name=unknown"`
+
+	// The number of 'Inqueue' PodGroups in this queue.
+	// +optional
+	Inqueue int32 `json:"inqueue,omitempty" protobuf:"bytes,5,opt,name=inqueue"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QueueList is a collection of queues.
+type QueueList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// items is the list of PodGroup
+	Items []Queue `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
@@ -20,6 +20,7 @@ package scheme
 
 import (
 	schedulingv1alpha1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	schedulingv1alpha2 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -55,6 +56,7 @@ func init() {
 // correctly.
 func AddToScheme(scheme *runtime.Scheme) {
 	schedulingv1alpha1.AddToScheme(scheme)
+	schedulingv1alpha2.AddToScheme(scheme)
 	corev1.AddToScheme(scheme)
 	policyv1beta1.AddToScheme(scheme)
 }
@@ -34,9 +34,9 @@ type FakePodGroups struct {
 	ns   string
 }
 
-var podgroupsResource = schema.GroupVersionResource{Group: "scheduling", Version: "v1alpha1", Resource: "podgroups"}
+var podgroupsResource = schema.GroupVersionResource{Group: "scheduling.incubator.k8s.io", Version: "v1alpha1", Resource: "podgroups"}
 
-var podgroupsKind = schema.GroupVersionKind{Group: "scheduling", Version: "v1alpha1", Kind: "PodGroup"}
+var podgroupsKind = schema.GroupVersionKind{Group: "scheduling.incubator.k8s.io", Version: "v1alpha1", Kind: "PodGroup"}
 
 // Get takes name of the podGroup, and returns the corresponding podGroup object, and an error if there is any.
 func (c *FakePodGroups) Get(name string, options v1.GetOptions) (result *v1alpha1.PodGroup, err error) {
@@ -131,7 +131,7 @@ func (c *FakePodGroups) DeleteCollection(options *v1.DeleteOptions, listOptions
 // Patch applies the patch and returns the patched podGroup.
 func (c *FakePodGroups) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.PodGroup, err error) {
 	obj, err := c.Fake.
-		Invokes(testing.NewPatchSubresourceAction(podgroupsResource, c.ns, name, data, subresources...), &v1alpha1.PodGroup{})
+		Invokes(testing.NewPatchSubresourceAction(podgroupsResource, c.ns, name, pt, data, subresources...), &v1alpha1.PodGroup{})
 
 	if obj == nil {
 		return nil, err
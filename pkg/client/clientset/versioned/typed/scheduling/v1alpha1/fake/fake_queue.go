@@ -33,9 +33,9 @@ type FakeQueues struct {
 	Fake *FakeSchedulingV1alpha1
 }
 
-var queuesResource = schema.GroupVersionResource{Group: "scheduling", Version: "v1alpha1", Resource: "queues"}
+var queuesResource = schema.GroupVersionResource{Group: "scheduling.incubator.k8s.io", Version: "v1alpha1", Resource: "queues"}
 
-var queuesKind = schema.GroupVersionKind{Group: "scheduling", Version: "v1alpha1", Kind: "Queue"}
+var queuesKind = schema.GroupVersionKind{Group: "scheduling.incubator.k8s.io", Version: "v1alpha1", Kind: "Queue"}
 
 // Get takes name of the queue, and returns the corresponding queue object, and an error if there is any.
 func (c *FakeQueues) Get(name string, options v1.GetOptions) (result *v1alpha1.Queue, err error) {
@@ -94,6 +94,17 @@ func (c *FakeQueues) Update(queue *v1alpha1.Queue) (result *v1alpha1.Queue, err
 	return obj.(*v1alpha1.Queue), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeQueues) UpdateStatus(queue *v1alpha1.Queue) (*v1alpha1.Queue, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(queuesResource, "status", queue), &v1alpha1.Queue{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Queue), err
+}
+
 // Delete takes name of the queue and deletes it. Returns an error if one occurs.
 func (c *FakeQueues) Delete(name string, options *v1.DeleteOptions) error {
 	_, err := c.Fake.
@@ -112,7 +123,7 @@ func (c *FakeQueues) DeleteCollection(options *v1.DeleteOptions, listOptions v1.
 // Patch applies the patch and returns the patched queue.
 func (c *FakeQueues) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.Queue, err error) {
 	obj, err := c.Fake.
-		Invokes(testing.NewRootPatchSubresourceAction(queuesResource, name, data, subresources...), &v1alpha1.Queue{})
+		Invokes(testing.NewRootPatchSubresourceAction(queuesResource, name, pt, data, subresources...), &v1alpha1.Queue{})
 	if obj == nil {
 		return nil, err
 	}
@@ -21,3 +21,5 @@ package v1alpha1
 type PodGroupExpansion interface{}
 
 type QueueExpansion interface{}
+
+type ReservationExpansion interface{}
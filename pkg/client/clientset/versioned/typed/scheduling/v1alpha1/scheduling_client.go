@@ -29,6 +29,7 @@ type SchedulingV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	PodGroupsGetter
 	QueuesGetter
+	ReservationsGetter
 }
 
 // SchedulingV1alpha1Client is used to interact with features provided by the scheduling group.
@@ -44,6 +45,10 @@ func (c *SchedulingV1alpha1Client) Queues() QueueInterface {
 	return newQueues(c)
 }
 
+func (c *SchedulingV1alpha1Client) Reservations(namespace string) ReservationInterface {
+	return newReservations(c, namespace)
+}
+
 // NewForConfig creates a new SchedulingV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*SchedulingV1alpha1Client, error) {
 	config := *c
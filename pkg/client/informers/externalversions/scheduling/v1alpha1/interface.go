@@ -28,6 +28,8 @@ type Interface interface {
 	PodGroups() PodGroupInformer
 	// Queues returns a QueueInformer.
 	Queues() QueueInformer
+	// Reservations returns a ReservationInformer.
+	Reservations() ReservationInformer
 }
 
 type version struct {
@@ -50,3 +52,8 @@ func (v *version) PodGroups() PodGroupInformer {
 func (v *version) Queues() QueueInformer {
 	return &queueInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// Reservations returns a ReservationInformer.
+func (v *version) Reservations() ReservationInformer {
+	return &reservationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -29,3 +29,11 @@ type PodGroupNamespaceListerExpansion interface{}
 // QueueListerExpansion allows custom methods to be added to
 // QueueLister.
 type QueueListerExpansion interface{}
+
+// ReservationListerExpansion allows custom methods to be added to
+// ReservationLister.
+type ReservationListerExpansion interface{}
+
+// ReservationNamespaceListerExpansion allows custom methods to be added to
+// ReservationNamespaceLister.
+type ReservationNamespaceListerExpansion interface{}
@@ -26,6 +26,9 @@ import (
 
 type allocateAction struct {
 	ssn *framework.Session
+
+	// nodeSampler searches the feasible nodes for tasks across sessions.
+	nodeSampler util.NodeSampler
 }
 
 func New() *allocateAction {
@@ -42,10 +45,22 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 	glog.V(3).Infof("zoux Enter Allocate ...")
 	defer glog.V(3).Infof("zoux Leaving Allocate ...")
 
+	args := framework.GetArgOfActionFromConf(ssn.Configurations, alloc.Name())
+	nodesToFind, percentageOfNodesToFind := 0, 0
+	args.GetInt(&nodesToFind, util.NodesToFindArg)
+	args.GetInt(&percentageOfNodesToFind, util.PercentageOfNodesToFindArg)
+	alloc.nodeSampler.SetLimits(nodesToFind, percentageOfNodesToFind)
+
 	queues := util.NewPriorityQueue(ssn.QueueOrderFn)
 	jobsMap := map[api.QueueID]*util.PriorityQueue{}
 
 	for _, job := range ssn.Jobs {
+		// Only allocate resources to the jobs admitted by enqueue action.
+		if job.IsPending() {
+			glog.V(4).Infof("Job <%s/%s> is not enqueued, skip it.", job.Namespace, job.Name)
+			continue
+		}
+
 		if queue, found := ssn.Queues[job.Queue]; found {
 			queues.Push(queue)
 		} else {
@@ -106,12 +121,11 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 		glog.V(3).Infof("Try to allocate resource to %d tasks of Job <%v/%v>",
 			tasks.Len(), job.Namespace, job.Name)
 
+		nodes := util.GetNodeList(ssn.Nodes)
 		for !tasks.Empty() {
-			predicateNodes := []*api.NodeInfo{}
-			nodeScores := map[int][]*api.NodeInfo{}
-
 			task := tasks.Pop().(*api.TaskInfo)
 			assigned := false
+			permitRejected := false
 
 			glog.V(3).Infof("There are <%d> nodes for Job <%v/%v>",
 				len(ssn.Nodes), job.Namespace, job.Name)
@@ -123,43 +137,41 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 			if len(job.NodesFitDelta) > 0 {
 				job.NodesFitDelta = make(api.NodeResourceMap)
 			}
-			for _, node := range ssn.Nodes {
-				glog.V(3).Infof("Considering Task <%v/%v> on node <%v>: <%v> vs. <%v>",
-					task.Namespace, task.Name, node.Name, task.Resreq, node.Idle)
-
-				// TODO (k82cn): Enable eCache for performance improvement.
-				if err := ssn.PredicateFn(task, node); err != nil {
-					glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
-						task.Namespace, task.Name, node.Name, err)
-					continue
-				} else {
-					predicateNodes = append(predicateNodes, node)
-				}
+			// Do not allocate the task beyond the capability of its queue.
+			if !ssn.Allocatable(queue, task) {
+				glog.V(3).Infof("Task <%v/%v> is not allocatable in Queue <%v>, skip it.",
+					task.Namespace, task.Name, queue.Name)
+				break
 			}
-			for _, node := range predicateNodes {
-				score, err := ssn.NodeOrderFn(task, node)
-				if err != nil {
-					glog.V(3).Infof("Error in Calculating Priority for the node:%v", err)
-				} else {
-					nodeScores[score] = append(nodeScores[score], node)
-				}
+			if err := ssn.PreFilter(task); err != nil {
+				glog.V(3).Infof("Skip allocating Task <%v/%v>: %v", task.Namespace, task.Name, err)
+				break
 			}
+			predicateNodes := alloc.nodeSampler.PredicateNodes(task, nodes, ssn.PredicateFn)
+			ssn.PostFilter(task, predicateNodes)
+			nodeScores := util.PrioritizeNodes(task, predicateNodes, ssn.NodeOrderMapFn, ssn.NodeOrderReduceFn)
 			selectedNodes := util.SelectBestNode(nodeScores)
 			for _, node := range selectedNodes {
-				// Allocate idle resource to the task.
-				if task.InitResreq.LessEqual(node.Idle) {
+				// Allocate idle resource to the task, except the ones reserved for other jobs.
+				idle := ssn.IdleFor(task, node)
+				if task.InitResreq.LessEqual(idle) {
 					glog.V(3).Infof("Binding Task <%v/%v> to node <%v>",
 						task.Namespace, task.Name, node.Name)
 					if err := ssn.Allocate(task, node.Name); err != nil {
 						glog.Errorf("Failed to bind Task %v on %v in Session %v, err: %v",
 							task.UID, node.Name, ssn.UID, err)
+						// The allocations of job are rolled back, so drop it in this session.
+						if _, rejected := err.(*framework.PermitRejectedError); rejected {
+							permitRejected = true
+							break
+						}
 						continue
 					}
 					assigned = true
 					break
 				} else {
 					//store information about missing resources
-					job.NodesFitDelta[node.Name] = node.Idle.Clone()
+					job.NodesFitDelta[node.Name] = idle
 					job.NodesFitDelta[node.Name].FitDelta(task.Resreq)
 					glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s> with limited resources",
 						task.Namespace, task.Name, node.Name)
@@ -180,6 +192,12 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 				}
 			}
 
+			if permitRejected {
+				glog.V(3).Infof("Job <%v/%v> is rejected by plugins, skip it in Session <%v>.",
+					job.Namespace, job.Name, ssn.UID)
+				break
+			}
+
 			if !assigned {
 				break
 			}
@@ -31,11 +31,13 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions/enqueue"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/drf"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/gang"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/proportion"
 )
 
@@ -127,6 +129,11 @@ func (ftsu *fakeStatusUpdater) UpdatePodGroup(pg *kbv1.PodGroup) (*kbv1.PodGroup
 	return nil, nil
 }
 
+func (ftsu *fakeStatusUpdater) UpdateQueueStatus(queue *kbv1.Queue) (*kbv1.Queue, error) {
+	// do nothing here
+	return nil, nil
+}
+
 type fakeVolumeBinder struct {
 }
 
@@ -143,12 +150,13 @@ func TestAllocate(t *testing.T) {
 	defer framework.CleanupPluginBuilders()
 
 	tests := []struct {
-		name      string
-		podGroups []*kbv1.PodGroup
-		pods      []*v1.Pod
-		nodes     []*v1.Node
-		queues    []*kbv1.Queue
-		expected  map[string]string
+		name         string
+		podGroups    []*kbv1.PodGroup
+		pods         []*v1.Pod
+		nodes        []*v1.Node
+		queues       []*kbv1.Queue
+		reservations []*kbv1.Reservation
+		expected     map[string]string
 	}{
 		{
 			name: "one Job with two Pods on one node",
@@ -244,8 +252,392 @@ func TestAllocate(t *testing.T) {
 				"c1/p1": "n1",
 			},
 		},
+		{
+			name: "one Job limited by Queue capability",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c1", "p2", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+						Capability: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+			expected: map[string]string{
+				"c1/p1": "n1",
+			},
+		},
+		{
+			name: "one Job not allocated beyond Queue capability",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("600m", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c1", "p2", "", v1.PodPending, buildResourceList("600m", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+						Capability: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+			expected: map[string]string{
+				"c1/p1": "n1",
+			},
+		},
+		{
+			name: "one Job limited by parent Queue capability",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c1", "p2", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "root",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+						Capability: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+						Parent: "root",
+					},
+				},
+			},
+			expected: map[string]string{
+				"c1/p1": "n1",
+			},
+		},
+		{
+			name: "one Job exceeds cluster resources",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:        "c1",
+						MinResources: buildResourceList("4", "4G"),
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "two Jobs, one not enqueued",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+						MinResources: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("2"),
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg2",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+						MinResources: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("2"),
+						},
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c1", "p2", "", v1.PodPending, buildResourceList("1", "1G"), "pg2", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("3", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			expected: map[string]string{
+				"c1/p1": "n1",
+			},
+		},
+		{
+			name: "one failed Job",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+					},
+					Status: kbv1.PodGroupStatus{
+						Phase: kbv1.PodGroupFailed,
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "one Job in closed Queue",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+						State:  kbv1.QueueStateClosed,
+					},
+				},
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "backlogged Job does not take share of Queue",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:        "c1",
+						MinResources: buildResourceList("8", "16Gi"),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg2",
+						Namespace: "c2",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c2",
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c2", "p1", "", v1.PodPending, buildResourceList("1", "2G"), "pg2", make(map[string]string), make(map[string]string)),
+				buildPod("c2", "p2", "", v1.PodPending, buildResourceList("1", "2G"), "pg2", make(map[string]string), make(map[string]string)),
+				buildPod("c2", "p3", "", v1.PodPending, buildResourceList("1", "2G"), "pg2", make(map[string]string), make(map[string]string)),
+				buildPod("c2", "p4", "", v1.PodPending, buildResourceList("1", "2G"), "pg2", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("4", "8Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c2",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			expected: map[string]string{
+				"c2/p1": "n1",
+				"c2/p2": "n1",
+				"c2/p3": "n1",
+				"c2/p4": "n1",
+			},
+		},
+		{
+			name: "two Jobs, resources reserved for one",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:     "c1",
+						MinMember: 2,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg2",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue: "c1",
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c1", "p2", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+				buildPod("c1", "p3", "", v1.PodPending, buildResourceList("2", "1G"), "pg2", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("3", "4Gi"), make(map[string]string)),
+			},
+			queues: []*kbv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+					},
+					Spec: kbv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			reservations: []*kbv1.Reservation{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "r1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.ReservationSpec{
+						PodGroup:  "pg1",
+						Resources: buildResourceList("2", "2G"),
+					},
+				},
+			},
+			expected: map[string]string{
+				"c1/p1": "n1",
+				"c1/p2": "n1",
+			},
+		},
 	}
 
+	enq := enqueue.New()
 	allocate := New()
 
 	for i, test := range tests {
@@ -257,6 +649,7 @@ func TestAllocate(t *testing.T) {
 			Nodes:         make(map[string]*api.NodeInfo),
 			Jobs:          make(map[api.JobID]*api.JobInfo),
 			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Reservations:  make(map[api.ReservationID]*api.ReservationInfo),
 			Binder:        binder,
 			StatusUpdater: &fakeStatusUpdater{},
 			VolumeBinder:  &fakeVolumeBinder{},
@@ -278,6 +671,10 @@ func TestAllocate(t *testing.T) {
 			schedulerCache.AddQueue(q)
 		}
 
+		for _, r := range test.reservations {
+			schedulerCache.AddReservation(r)
+		}
+
 		ssn := framework.OpenSession(schedulerCache, []conf.Tier{
 			{
 				Plugins: []conf.PluginOption{
@@ -289,9 +686,10 @@ func TestAllocate(t *testing.T) {
 					},
 				},
 			},
-		})
+		}, nil)
 		defer framework.CloseSession(ssn)
 
+		enq.Execute(ssn)
 		allocate.Execute(ssn)
 
 		for i := 0; i < len(test.expected); i++ {
@@ -307,3 +705,173 @@ func TestAllocate(t *testing.T) {
 		}
 	}
 }
+
+// rejectingPlugin rejects the task of the name in PreFilterFn.
+type rejectingPlugin struct {
+	name string
+}
+
+func (rp *rejectingPlugin) Name() string { return "rejecting" }
+func (rp *rejectingPlugin) OnSessionOpen(ssn *framework.Session) {
+	ssn.AddPreFilterFn(rp.Name(), func(task *api.TaskInfo) error {
+		if task.Name == rp.name {
+			return fmt.Errorf("task %s is rejected", task.Name)
+		}
+		return nil
+	})
+}
+func (rp *rejectingPlugin) OnSessionClose(ssn *framework.Session) {}
+
+func TestAllocateSkipsRejectedTask(t *testing.T) {
+	framework.RegisterPluginBuilder("drf", drf.New)
+	framework.RegisterPluginBuilder("proportion", proportion.New)
+	framework.RegisterPluginBuilder("rejecting", func(map[string]string) framework.Plugin {
+		return &rejectingPlugin{name: "p1"}
+	})
+	defer framework.CleanupPluginBuilders()
+
+	binder := &fakeBinder{
+		binds: map[string]string{},
+		c:     make(chan string),
+	}
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		Reservations:  make(map[api.ReservationID]*api.ReservationInfo),
+		Binder:        binder,
+		StatusUpdater: &fakeStatusUpdater{},
+		VolumeBinder:  &fakeVolumeBinder{},
+
+		Recorder: record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)))
+	schedulerCache.AddQueue(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+		Spec:       kbv1.QueueSpec{Weight: 1},
+	})
+	for _, name := range []string{"pg1", "pg2"} {
+		schedulerCache.AddPodGroup(&kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "c1",
+			},
+			Spec: kbv1.PodGroupSpec{
+				Queue: "c1",
+			},
+		})
+	}
+	schedulerCache.AddPod(buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)))
+	schedulerCache.AddPod(buildPod("c1", "p2", "", v1.PodPending, buildResourceList("1", "1G"), "pg2", make(map[string]string), make(map[string]string)))
+
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{Name: "drf"},
+				{Name: "proportion"},
+				{Name: "rejecting"},
+			},
+		},
+	}, nil)
+	defer framework.CloseSession(ssn)
+
+	enqueue.New().Execute(ssn)
+	New().Execute(ssn)
+
+	select {
+	case <-binder.c:
+	case <-time.After(3 * time.Second):
+		t.Errorf("Failed to get binding request.")
+	}
+
+	// The rejected task is skipped, and the other job is still allocated.
+	expected := map[string]string{"c1/p2": "n1"}
+	if !reflect.DeepEqual(expected, binder.binds) {
+		t.Errorf("expected: %v, got %v", expected, binder.binds)
+	}
+	if status := ssn.Jobs["c1/pg1"].Tasks["c1-p1"].Status; status != api.Pending {
+		t.Errorf("expected rejected task to be %v, got %v", api.Pending, status)
+	}
+}
+
+// permitRejectingPlugin rejects the binding of all tasks by PermitFn.
+type permitRejectingPlugin struct{}
+
+func (rp *permitRejectingPlugin) Name() string { return "permit-rejecting" }
+func (rp *permitRejectingPlugin) OnSessionOpen(ssn *framework.Session) {
+	ssn.AddPermitFn(rp.Name(), func(task *api.TaskInfo) *api.PermitResult {
+		return &api.PermitResult{Code: api.PermitReject, Message: "rejected for test"}
+	})
+}
+func (rp *permitRejectingPlugin) OnSessionClose(ssn *framework.Session) {}
+
+func TestAllocatePermitRejected(t *testing.T) {
+	framework.RegisterPluginBuilder("gang", gang.New)
+	framework.RegisterPluginBuilder("permit-rejecting", func(map[string]string) framework.Plugin {
+		return &permitRejectingPlugin{}
+	})
+	defer framework.CleanupPluginBuilders()
+
+	binder := &fakeBinder{
+		binds: map[string]string{},
+		c:     make(chan string),
+	}
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		Reservations:  make(map[api.ReservationID]*api.ReservationInfo),
+		Binder:        binder,
+		StatusUpdater: &fakeStatusUpdater{},
+		VolumeBinder:  &fakeVolumeBinder{},
+
+		Recorder: record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(buildNode("n1", buildResourceList("2", "4Gi"), make(map[string]string)))
+	schedulerCache.AddNode(buildNode("n2", buildResourceList("2", "4Gi"), make(map[string]string)))
+	schedulerCache.AddPod(buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)))
+	schedulerCache.AddPod(buildPod("c1", "p2", "", v1.PodPending, buildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)))
+	schedulerCache.AddPodGroup(&kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg1",
+			Namespace: "c1",
+		},
+		Spec: kbv1.PodGroupSpec{
+			MinMember: 2,
+			Queue:     "c1",
+		},
+	})
+	schedulerCache.AddQueue(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "c1",
+		},
+		Spec: kbv1.QueueSpec{
+			Weight: 1,
+		},
+	})
+
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{Name: "gang"},
+				{Name: "permit-rejecting"},
+			},
+		},
+	}, nil)
+	defer framework.CloseSession(ssn)
+
+	enqueue.New().Execute(ssn)
+	New().Execute(ssn)
+
+	for _, job := range ssn.Jobs {
+		if allocated := job.TaskStatusIndex[api.Allocated]; len(allocated) != 0 {
+			t.Errorf("expected no task of Job <%s/%s> allocated, got %d",
+				job.Namespace, job.Name, len(allocated))
+		}
+	}
+	select {
+	case key := <-binder.c:
+		t.Errorf("unexpected binding request of %s", key)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -17,14 +17,20 @@ limitations under the License.
 package backfill
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/util"
 )
 
 type backfillAction struct {
 	ssn *framework.Session
+
+	// nodeSampler searches the feasible nodes for tasks across sessions.
+	nodeSampler util.NodeSampler
 }
 
 func New() *backfillAction {
@@ -41,24 +47,54 @@ func (alloc *backfillAction) Execute(ssn *framework.Session) {
 	glog.V(3).Infof("Enter Backfill ...")
 	defer glog.V(3).Infof("Leaving Backfill ...")
 
+	args := framework.GetArgOfActionFromConf(ssn.Configurations, alloc.Name())
+	// The task is bound to the first feasible node, so only one node is searched
+	// by default.
+	nodesToFind, percentageOfNodesToFind := 1, 0
+	args.GetInt(&nodesToFind, util.NodesToFindArg)
+	args.GetInt(&percentageOfNodesToFind, util.PercentageOfNodesToFindArg)
+	alloc.nodeSampler.SetLimits(nodesToFind, percentageOfNodesToFind)
+
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		// Leave the nodes with resources reserved for other jobs to their owners,
+		// as the task would compete with them for the reserved resources.
+		if ssn.ReservedForOthers(task, node) {
+			return fmt.Errorf("resources on node <%s> are reserved for other jobs", node.Name)
+		}
+		return ssn.PredicateFn(task, node)
+	}
+
 	// TODO (k82cn): When backfill, it's also need to balance between Queues.
+	nodes := util.GetNodeList(ssn.Nodes)
 	for _, job := range ssn.Jobs {
+		// Like allocate, only backfill the jobs admitted by enqueue action.
+		if job.IsPending() {
+			glog.V(4).Infof("Job <%s/%s> is not enqueued, skip it.", job.Namespace, job.Name)
+			continue
+		}
+
+		permitRejected := false
 		for _, task := range job.TaskStatusIndex[api.Pending] {
 			if task.InitResreq.IsEmpty() {
 				// As task did not request resources, so it only need to meet predicates.
 				// TODO (k82cn): need to prioritize nodes to avoid pod hole.
-				for _, node := range ssn.Nodes {
-					// TODO (k82cn): predicates did not consider pod number for now, there'll
-					// be ping-pong case here.
-					if err := ssn.PredicateFn(task, node); err != nil {
-						glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
-							task.Namespace, task.Name, node.Name, err)
-						continue
-					}
-
+				if err := ssn.PreFilter(task); err != nil {
+					glog.V(3).Infof("Skip backfilling Task <%v/%v>: %v", task.Namespace, task.Name, err)
+					continue
+				}
+				// TODO (k82cn): predicates did not consider pod number for now, there'll
+				// be ping-pong case here.
+				predicateNodes := alloc.nodeSampler.PredicateNodes(task, nodes, predicateFn)
+				ssn.PostFilter(task, predicateNodes)
+				for _, node := range predicateNodes {
 					glog.V(3).Infof("Binding Task <%v/%v> to node <%v>", task.Namespace, task.Name, node.Name)
 					if err := ssn.Allocate(task, node.Name); err != nil {
 						glog.Errorf("Failed to bind Task %v on %v in Session %v", task.UID, node.Name, ssn.UID)
+						// The allocations of job are rolled back, so drop it in this session.
+						if _, rejected := err.(*framework.PermitRejectedError); rejected {
+							permitRejected = true
+							break
+						}
 						continue
 					}
 					break
@@ -66,6 +102,12 @@ func (alloc *backfillAction) Execute(ssn *framework.Session) {
 			} else {
 				// TODO (k82cn): backfill for other case.
 			}
+
+			if permitRejected {
+				glog.V(3).Infof("Job <%v/%v> is rejected by plugins, skip it in Session <%v>.",
+					job.Namespace, job.Name, ssn.UID)
+				break
+			}
 		}
 	}
 }
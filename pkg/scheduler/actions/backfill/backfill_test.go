@@ -0,0 +1,268 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfill
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+)
+
+func buildResourceList(cpu string, memory string) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func buildNode(name string, alloc v1.ResourceList) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: v1.NodeStatus{
+			Capacity:    alloc,
+			Allocatable: alloc,
+		},
+	}
+}
+
+// buildPod builds a BestEffort pod, which does not request resources.
+func buildPod(ns, n, groupName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(fmt.Sprintf("%v-%v", ns, n)),
+			Name:      n,
+			Namespace: ns,
+			Annotations: map[string]string{
+				kbv1.GroupNameAnnotationKey: groupName,
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{}},
+		},
+	}
+}
+
+func buildPodGroup(ns, name string) *kbv1.PodGroup {
+	return &kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: kbv1.PodGroupSpec{
+			Queue: "c1",
+		},
+		Status: kbv1.PodGroupStatus{
+			Phase: kbv1.PodGroupInqueue,
+		},
+	}
+}
+
+func buildReservation(ns, name, group string, req v1.ResourceList) *kbv1.Reservation {
+	return &kbv1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: kbv1.ReservationSpec{
+			PodGroup:  group,
+			Resources: req,
+		},
+	}
+}
+
+type fakeBinder struct {
+	sync.Mutex
+	binds map[string]string
+	c     chan string
+}
+
+func (fb *fakeBinder) Bind(p *v1.Pod, hostname string) error {
+	fb.Lock()
+	defer fb.Unlock()
+
+	key := fmt.Sprintf("%v/%v", p.Namespace, p.Name)
+	fb.binds[key] = hostname
+
+	fb.c <- key
+
+	return nil
+}
+
+type fakeStatusUpdater struct {
+}
+
+func (ftsu *fakeStatusUpdater) UpdatePodCondition(pod *v1.Pod, podCondition *v1.PodCondition) (*v1.Pod, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func (ftsu *fakeStatusUpdater) UpdatePodGroup(pg *kbv1.PodGroup) (*kbv1.PodGroup, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func (ftsu *fakeStatusUpdater) UpdateQueueStatus(queue *kbv1.Queue) (*kbv1.Queue, error) {
+	// do nothing here
+	return nil, nil
+}
+
+type fakeVolumeBinder struct {
+}
+
+func (fvb *fakeVolumeBinder) AllocateVolumes(task *api.TaskInfo, hostname string) error {
+	return nil
+}
+func (fvb *fakeVolumeBinder) BindVolumes(task *api.TaskInfo) error {
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+	tests := []struct {
+		name         string
+		podGroups    []*kbv1.PodGroup
+		pods         []*v1.Pod
+		nodes        []*v1.Node
+		reservations []*kbv1.Reservation
+		expected     map[string]string
+	}{
+		{
+			name:      "BestEffort task backfilled",
+			podGroups: []*kbv1.PodGroup{buildPodGroup("c1", "pg1")},
+			pods:      []*v1.Pod{buildPod("c1", "p1", "pg1")},
+			nodes:     []*v1.Node{buildNode("n1", buildResourceList("2", "4Gi"))},
+			expected: map[string]string{
+				"c1/p1": "n1",
+			},
+		},
+		{
+			name:      "BestEffort task not backfilled to node reserved for other job",
+			podGroups: []*kbv1.PodGroup{buildPodGroup("c1", "pg1"), buildPodGroup("c1", "pg2")},
+			pods:      []*v1.Pod{buildPod("c1", "p1", "pg1")},
+			nodes: []*v1.Node{
+				buildNode("n1", buildResourceList("2", "4Gi")),
+				buildNode("n2", buildResourceList("2", "4Gi")),
+			},
+			reservations: []*kbv1.Reservation{
+				buildReservation("c1", "r1", "pg2", buildResourceList("1", "1Gi")),
+			},
+			expected: map[string]string{
+				"c1/p1": "n2",
+			},
+		},
+		{
+			name:      "BestEffort task backfilled to node reserved for its job",
+			podGroups: []*kbv1.PodGroup{buildPodGroup("c1", "pg1")},
+			pods:      []*v1.Pod{buildPod("c1", "p1", "pg1")},
+			nodes:     []*v1.Node{buildNode("n1", buildResourceList("2", "4Gi"))},
+			reservations: []*kbv1.Reservation{
+				buildReservation("c1", "r1", "pg1", buildResourceList("1", "1Gi")),
+			},
+			expected: map[string]string{
+				"c1/p1": "n1",
+			},
+		},
+		{
+			name:      "BestEffort task not backfilled when all nodes are reserved for other jobs",
+			podGroups: []*kbv1.PodGroup{buildPodGroup("c1", "pg1"), buildPodGroup("c1", "pg2")},
+			pods:      []*v1.Pod{buildPod("c1", "p1", "pg1")},
+			nodes:     []*v1.Node{buildNode("n1", buildResourceList("2", "4Gi"))},
+			reservations: []*kbv1.Reservation{
+				buildReservation("c1", "r1", "pg2", buildResourceList("1", "1Gi")),
+			},
+			expected: map[string]string{},
+		},
+	}
+
+	for i, test := range tests {
+		// The search of nodes starts from the first node in every case.
+		backfill := New()
+
+		binder := &fakeBinder{
+			binds: map[string]string{},
+			c:     make(chan string),
+		}
+		schedulerCache := &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Reservations:  make(map[api.ReservationID]*api.ReservationInfo),
+			Binder:        binder,
+			StatusUpdater: &fakeStatusUpdater{},
+			VolumeBinder:  &fakeVolumeBinder{},
+
+			Recorder: record.NewFakeRecorder(100),
+		}
+		for _, node := range test.nodes {
+			schedulerCache.AddNode(node)
+		}
+		for _, pod := range test.pods {
+			schedulerCache.AddPod(pod)
+		}
+		for _, pg := range test.podGroups {
+			schedulerCache.AddPodGroup(pg)
+		}
+		schedulerCache.AddQueue(&kbv1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+			Spec:       kbv1.QueueSpec{Weight: 1},
+		})
+		for _, r := range test.reservations {
+			schedulerCache.AddReservation(r)
+		}
+
+		ssn := framework.OpenSession(schedulerCache, []conf.Tier{}, nil)
+
+		backfill.Execute(ssn)
+
+		for i := 0; i < len(test.expected); i++ {
+			select {
+			case <-binder.c:
+			case <-time.After(3 * time.Second):
+				t.Errorf("Failed to get binding request.")
+			}
+		}
+		select {
+		case key := <-binder.c:
+			t.Errorf("case %d (%s): unexpected binding request of %s", i, test.name, key)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if !reflect.DeepEqual(test.expected, binder.binds) {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, test.name, test.expected, binder.binds)
+		}
+
+		framework.CloseSession(ssn)
+	}
+}
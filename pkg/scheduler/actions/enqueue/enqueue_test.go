@@ -0,0 +1,236 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/gang"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/proportion"
+)
+
+func buildResourceList(cpu string, memory string) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func buildNode(name string, alloc v1.ResourceList) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: v1.NodeStatus{
+			Capacity:    alloc,
+			Allocatable: alloc,
+		},
+	}
+}
+
+func buildPod(ns, n string, req v1.ResourceList, groupName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(fmt.Sprintf("%v-%v", ns, n)),
+			Name:      n,
+			Namespace: ns,
+			Annotations: map[string]string{
+				kbv1.GroupNameAnnotationKey: groupName,
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: req,
+					},
+				},
+			},
+		},
+	}
+}
+
+type fakeStatusUpdater struct {
+}
+
+func (ftsu *fakeStatusUpdater) UpdatePodCondition(pod *v1.Pod, podCondition *v1.PodCondition) (*v1.Pod, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func (ftsu *fakeStatusUpdater) UpdatePodGroup(pg *kbv1.PodGroup) (*kbv1.PodGroup, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func (ftsu *fakeStatusUpdater) UpdateQueueStatus(queue *kbv1.Queue) (*kbv1.Queue, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func TestEnqueue(t *testing.T) {
+	framework.RegisterPluginBuilder("gang", gang.New)
+	framework.RegisterPluginBuilder("proportion", proportion.New)
+	defer framework.CleanupPluginBuilders()
+
+	tests := []struct {
+		name      string
+		podGroups []*kbv1.PodGroup
+		pods      []*v1.Pod
+		expected  map[string]kbv1.PodGroupPhase
+	}{
+		{
+			name: "gang without pods is admitted",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:        "c1",
+						MinMember:    2,
+						MinResources: buildResourceList("2", "2G"),
+					},
+				},
+			},
+			expected: map[string]kbv1.PodGroupPhase{
+				"c1/pg1": kbv1.PodGroupInqueue,
+			},
+		},
+		{
+			name: "gang with part of pods is admitted",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:     "c1",
+						MinMember: 2,
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				buildPod("c1", "p1", buildResourceList("1", "1G"), "pg1"),
+			},
+			expected: map[string]kbv1.PodGroupPhase{
+				"c1/pg1": kbv1.PodGroupInqueue,
+			},
+		},
+		{
+			name: "gang exceeding queue resources is not admitted",
+			podGroups: []*kbv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:        "c1",
+						MinMember:    2,
+						MinResources: buildResourceList("2", "2G"),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg2",
+						Namespace: "c1",
+					},
+					Spec: kbv1.PodGroupSpec{
+						Queue:        "c1",
+						MinMember:    2,
+						MinResources: buildResourceList("2", "2G"),
+					},
+				},
+			},
+			expected: map[string]kbv1.PodGroupPhase{
+				"c1/pg1": kbv1.PodGroupInqueue,
+				"c1/pg2": kbv1.PodGroupPending,
+			},
+		},
+	}
+
+	enq := New()
+
+	for _, test := range tests {
+		schedulerCache := &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			StatusUpdater: &fakeStatusUpdater{},
+
+			Recorder: record.NewFakeRecorder(100),
+		}
+		schedulerCache.AddNode(buildNode("n1", buildResourceList("3", "4G")))
+		schedulerCache.AddQueue(&kbv1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+			Spec:       kbv1.QueueSpec{Weight: 1},
+		})
+		for _, pod := range test.pods {
+			schedulerCache.AddPod(pod)
+		}
+		for _, pg := range test.podGroups {
+			schedulerCache.AddPodGroup(pg)
+		}
+
+		ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+			{
+				Plugins: []conf.PluginOption{
+					{Name: "gang"},
+					{Name: "proportion"},
+				},
+			},
+		}, nil)
+
+		enq.Execute(ssn)
+
+		phases := map[string]kbv1.PodGroupPhase{}
+		for _, job := range ssn.Jobs {
+			phase := job.PodGroup.Status.Phase
+			if phase == "" {
+				phase = kbv1.PodGroupPending
+			}
+			phases[fmt.Sprintf("%s/%s", job.Namespace, job.PodGroup.Name)] = phase
+		}
+
+		for name, expected := range test.expected {
+			if phases[name] != expected {
+				t.Errorf("case %s: expected phase %s of PodGroup %s, got %s",
+					test.name, expected, name, phases[name])
+			}
+		}
+
+		framework.CloseSession(ssn)
+	}
+}
@@ -21,13 +21,34 @@ import (
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions/allocate"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions/backfill"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions/enqueue"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions/preempt"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions/reclaim"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/util"
 )
 
+// nodeSamplerArguments are the arguments of the actions searching feasible nodes by util.NodeSampler.
+var nodeSamplerArguments = []framework.ArgumentSpec{
+	{
+		Name:        util.NodesToFindArg,
+		Type:        framework.IntArgument,
+		Description: "Stop searching feasible nodes for a task after so many nodes are found, 0 for no limit",
+	},
+	{
+		Name:        util.PercentageOfNodesToFindArg,
+		Type:        framework.IntArgument,
+		Description: "Stop searching feasible nodes for a task after the percentage (1-100) of nodes are found, 0 for no limit",
+	},
+}
+
 func init() {
 	framework.RegisterAction(reclaim.New())
+	framework.RegisterAction(enqueue.New())
 	framework.RegisterAction(allocate.New())
 	framework.RegisterAction(backfill.New())
 	framework.RegisterAction(preempt.New())
+
+	framework.RegisterActionArguments("allocate", nodeSamplerArguments...)
+	framework.RegisterActionArguments("backfill", nodeSamplerArguments...)
+	framework.RegisterActionArguments("preempt", nodeSamplerArguments...)
 }
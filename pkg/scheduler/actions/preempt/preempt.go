@@ -29,6 +29,9 @@ import (
 
 type preemptAction struct {
 	ssn *framework.Session
+
+	// nodeSampler searches the feasible nodes for tasks across sessions.
+	nodeSampler util.NodeSampler
 }
 
 func New() *preemptAction {
@@ -45,6 +48,12 @@ func (alloc *preemptAction) Execute(ssn *framework.Session) {
 	glog.V(3).Infof("Enter Preempt ...")
 	defer glog.V(3).Infof("Leaving Preempt ...")
 
+	args := framework.GetArgOfActionFromConf(ssn.Configurations, alloc.Name())
+	nodesToFind, percentageOfNodesToFind := 0, 0
+	args.GetInt(&nodesToFind, util.NodesToFindArg)
+	args.GetInt(&percentageOfNodesToFind, util.PercentageOfNodesToFindArg)
+	alloc.nodeSampler.SetLimits(nodesToFind, percentageOfNodesToFind)
+
 	preemptorsMap := map[api.QueueID]*util.PriorityQueue{}
 	preemptorTasks := map[api.JobID]*util.PriorityQueue{}
 
@@ -98,7 +107,7 @@ func (alloc *preemptAction) Execute(ssn *framework.Session) {
 
 				preemptor := preemptorTasks[preemptorJob.UID].Pop().(*api.TaskInfo)
 
-				if preempted, _ := preempt(ssn, stmt, preemptor, ssn.Nodes, func(task *api.TaskInfo) bool {
+				if preempted, _ := preempt(ssn, stmt, preemptor, ssn.Nodes, &alloc.nodeSampler, func(task *api.TaskInfo) bool {
 					// Ignore non running task.
 					if task.Status != api.Running {
 						return false
@@ -146,7 +155,7 @@ func (alloc *preemptAction) Execute(ssn *framework.Session) {
 				preemptor := preemptorTasks[job.UID].Pop().(*api.TaskInfo)
 
 				stmt := ssn.Statement()
-				assigned, _ := preempt(ssn, stmt, preemptor, ssn.Nodes, func(task *api.TaskInfo) bool {
+				assigned, _ := preempt(ssn, stmt, preemptor, ssn.Nodes, &alloc.nodeSampler, func(task *api.TaskInfo) bool {
 					// Ignore non running task.
 					if task.Status != api.Running {
 						return false
@@ -173,29 +182,27 @@ func preempt(
 	stmt *framework.Statement,
 	preemptor *api.TaskInfo,
 	nodes map[string]*api.NodeInfo,
+	nodeSampler *util.NodeSampler,
 	filter func(*api.TaskInfo) bool,
 ) (bool, error) {
-	predicateNodes := []*api.NodeInfo{}
-	nodeScores := map[int][]*api.NodeInfo{}
 	assigned := false
 
-	for _, node := range nodes {
-		if err := ssn.PredicateFn(preemptor, node); err != nil {
-			glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
-				preemptor.Namespace, preemptor.Name, node.Name, err)
-			continue
-		} else {
-			predicateNodes = append(predicateNodes, node)
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		// The resources released by victims on the nodes with resources reserved for
+		// other jobs may be reserved for them instead of the preemptor.
+		if ssn.ReservedForOthers(task, node) {
+			return fmt.Errorf("resources on node <%s> are reserved for other jobs", node.Name)
 		}
+		return ssn.PredicateFn(task, node)
 	}
-	for _, node := range predicateNodes {
-		score, err := ssn.NodeOrderFn(preemptor, node)
-		if err != nil {
-			glog.V(3).Infof("Error in Calculating Priority for the node:%v", err)
-		} else {
-			nodeScores[score] = append(nodeScores[score], node)
-		}
+
+	if err := ssn.PreFilter(preemptor); err != nil {
+		glog.V(3).Infof("Skip preempting for Task <%s/%s>: %v", preemptor.Namespace, preemptor.Name, err)
+		return false, err
 	}
+	predicateNodes := nodeSampler.PredicateNodes(preemptor, util.GetNodeList(nodes), predicateFn)
+	ssn.PostFilter(preemptor, predicateNodes)
+	nodeScores := util.PrioritizeNodes(preemptor, predicateNodes, ssn.NodeOrderMapFn, ssn.NodeOrderReduceFn)
 	selectedNodes := util.SelectBestNode(nodeScores)
 	for _, node := range selectedNodes {
 		glog.V(3).Infof("Considering Task <%s/%s> on Node <%s>.",
@@ -231,7 +238,8 @@ func preempt(
 			preemptee := victimsQueue.Pop().(*api.TaskInfo)
 			glog.Errorf("Try to preempt Task <%s/%s> for Tasks <%s/%s>",
 				preemptee.Namespace, preemptee.Name, preemptor.Namespace, preemptor.Name)
-			if err := stmt.Evict(preemptee, "preempt"); err != nil {
+			reason := fmt.Sprintf("preempted by Task <%s/%s>", preemptor.Namespace, preemptor.Name)
+			if err := stmt.Evict(preemptee, reason); err != nil {
 				glog.Errorf("Failed to preempt Task <%s/%s> for Tasks <%s/%s>: %v",
 					preemptee.Namespace, preemptee.Name, preemptor.Namespace, preemptor.Name, err)
 				continue
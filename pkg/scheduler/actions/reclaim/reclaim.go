@@ -17,6 +17,8 @@ limitations under the License.
 package reclaim
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
@@ -157,7 +159,8 @@ func (alloc *reclaimAction) Execute(ssn *framework.Session) {
 			for _, reclaimee := range victims {
 				glog.Errorf("Try to reclaim Task <%s/%s> for Tasks <%s/%s>",
 					reclaimee.Namespace, reclaimee.Name, task.Namespace, task.Name)
-				if err := ssn.Evict(reclaimee, "reclaim"); err != nil {
+				reason := fmt.Sprintf("reclaimed by Task <%s/%s>", task.Namespace, task.Name)
+				if err := ssn.Evict(reclaimee, reason); err != nil {
 					glog.Errorf("Failed to reclaim Task <%s/%s> for Tasks <%s/%s>: %v",
 						reclaimee.Namespace, reclaimee.Name, task.Namespace, task.Name, err)
 					continue
@@ -22,6 +22,12 @@ type ClusterInfo struct {
 	Jobs   map[JobID]*JobInfo
 	Nodes  map[string]*NodeInfo
 	Queues map[QueueID]*QueueInfo
+
+	Reservations map[ReservationID]*ReservationInfo
+
+	// DisruptionBudgets are the PodDisruptionBudgets honored by evictions; it's
+	// empty if evictions bypass the budgets.
+	DisruptionBudgets []*DisruptionBudgetInfo
 }
 
 func (ci ClusterInfo) String() string {
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DisruptionBudgetInfo is the scheduler view of PodDisruptionBudget; it tracks
+// how many more pods covered by the budget may be evicted.
+type DisruptionBudgetInfo struct {
+	Name      string
+	Namespace string
+
+	Selector labels.Selector
+
+	// Allowed is the number of disruptions still allowed by the budget.
+	Allowed int32
+}
+
+// NewDisruptionBudgetInfo creates a DisruptionBudgetInfo by PodDisruptionBudget.
+func NewDisruptionBudgetInfo(pdb *policyv1.PodDisruptionBudget) (*DisruptionBudgetInfo, error) {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisruptionBudgetInfo{
+		Name:      pdb.Name,
+		Namespace: pdb.Namespace,
+		Selector:  selector,
+		Allowed:   pdb.Status.PodDisruptionsAllowed,
+	}, nil
+}
+
+// Covers returns whether the task is covered by the budget.
+func (dbi *DisruptionBudgetInfo) Covers(task *TaskInfo) bool {
+	if task.Pod == nil || task.Namespace != dbi.Namespace {
+		return false
+	}
+
+	// An empty selector matches nothing, the same as the disruption controller.
+	if dbi.Selector.Empty() {
+		return false
+	}
+
+	return dbi.Selector.Matches(labels.Set(task.Pod.Labels))
+}
+
+// Clone is used to clone a DisruptionBudgetInfo.
+func (dbi *DisruptionBudgetInfo) Clone() *DisruptionBudgetInfo {
+	return &DisruptionBudgetInfo{
+		Name:      dbi.Name,
+		Namespace: dbi.Namespace,
+		Selector:  dbi.Selector,
+		Allowed:   dbi.Allowed,
+	}
+}
+
+func (dbi *DisruptionBudgetInfo) String() string {
+	return fmt.Sprintf("PodDisruptionBudget (%s/%s): allowed %d",
+		dbi.Namespace, dbi.Name, dbi.Allowed)
+}
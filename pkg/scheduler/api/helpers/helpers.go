@@ -29,6 +29,30 @@ func Min(l, r *api.Resource) *api.Resource {
 	res.MilliGPU = math.Min(l.MilliGPU, r.MilliGPU)
 	res.Memory = math.Min(l.Memory, r.Memory)
 
+	for rName, lQuant := range l.ScalarResources {
+		res.SetScalar(rName, math.Min(lQuant, r.ScalarResources[rName]))
+	}
+	for rName, rQuant := range r.ScalarResources {
+		res.SetScalar(rName, math.Min(rQuant, l.ScalarResources[rName]))
+	}
+
+	return res
+}
+
+func Max(l, r *api.Resource) *api.Resource {
+	res := &api.Resource{}
+
+	res.MilliCPU = math.Max(l.MilliCPU, r.MilliCPU)
+	res.MilliGPU = math.Max(l.MilliGPU, r.MilliGPU)
+	res.Memory = math.Max(l.Memory, r.Memory)
+
+	for rName, lQuant := range l.ScalarResources {
+		res.SetScalar(rName, math.Max(lQuant, r.ScalarResources[rName]))
+	}
+	for rName, rQuant := range r.ScalarResources {
+		res.SetScalar(rName, math.Max(rQuant, l.ScalarResources[rName]))
+	}
+
 	return res
 }
 
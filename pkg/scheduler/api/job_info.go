@@ -63,9 +63,14 @@ func getJobID(pod *v1.Pod) JobID {
 	return ""
 }
 
+// NewTaskInfo creates a TaskInfo with the resources calculated by DefaultPodRequest.
 func NewTaskInfo(pod *v1.Pod) *TaskInfo {
-	req := GetPodResourceWithoutInitContainers(pod)
-	initResreq := GetPodResourceRequest(pod)
+	return NewTaskInfoWithRequest(pod, DefaultPodRequest)
+}
+
+// NewTaskInfoWithRequest creates a TaskInfo with the resources calculated by podRequest.
+func NewTaskInfoWithRequest(pod *v1.Pod, podRequest PodRequestFn) *TaskInfo {
+	req, initResreq := podRequest(pod)
 
 	jobID := getJobID(pod)
 
@@ -129,6 +134,8 @@ type JobInfo struct {
 
 	NodeSelector map[string]string
 	MinAvailable int32
+	// MinResources is the minimal resources to run the job; it's nil if not defined.
+	MinResources *Resource
 
 	NodesFitDelta NodeResourceMap
 
@@ -144,6 +151,10 @@ type JobInfo struct {
 
 	// TODO(k82cn): keep backward compatibility, removed it when v1alpha1 finalized.
 	PDB *policyv1.PodDisruptionBudget
+
+	// Generation is increased by every change of the job, so the unchanged
+	// job can be shared by snapshots.
+	Generation int64
 }
 
 func NewJobInfo(uid JobID, tasks ...*TaskInfo) *JobInfo {
@@ -169,16 +180,32 @@ func NewJobInfo(uid JobID, tasks ...*TaskInfo) *JobInfo {
 
 func (ji *JobInfo) UnsetPodGroup() {
 	ji.PodGroup = nil
+	ji.Generation++
 }
 
 func (ji *JobInfo) SetPodGroup(pg *v1alpha1.PodGroup) {
 	ji.Name = pg.Name
 	ji.Namespace = pg.Namespace
 	ji.MinAvailable = pg.Spec.MinMember
+	ji.MinResources = nil
+	if len(pg.Spec.MinResources) != 0 {
+		ji.MinResources = NewResource(pg.Spec.MinResources)
+	}
 	ji.Queue = QueueID(pg.Spec.Queue)
 	ji.CreationTimestamp = pg.GetCreationTimestamp()
 
 	ji.PodGroup = pg
+	ji.Generation++
+}
+
+// IsPending returns whether the job is waiting to be admitted by scheduler;
+// jobs using PDB are always admitted.
+func (ji *JobInfo) IsPending() bool {
+	if ji.PodGroup == nil {
+		return false
+	}
+
+	return ji.PodGroup.Status.Phase == "" || ji.PodGroup.Status.Phase == v1alpha1.PodGroupPending
 }
 
 func (ji *JobInfo) SetPDB(pdb *policyv1.PodDisruptionBudget) {
@@ -188,10 +215,12 @@ func (ji *JobInfo) SetPDB(pdb *policyv1.PodDisruptionBudget) {
 
 	ji.CreationTimestamp = pdb.GetCreationTimestamp()
 	ji.PDB = pdb
+	ji.Generation++
 }
 
 func (ji *JobInfo) UnsetPDB() {
 	ji.PDB = nil
+	ji.Generation++
 }
 
 func (ji *JobInfo) GetTasks(statuses ...TaskStatus) []*TaskInfo {
@@ -225,6 +254,8 @@ func (ji *JobInfo) AddTaskInfo(ti *TaskInfo) {
 	if AllocatedStatus(ti.Status) {
 		ji.Allocated.Add(ti.Resreq)
 	}
+
+	ji.Generation++
 }
 
 func (ji *JobInfo) UpdateTaskStatus(task *TaskInfo, status TaskStatus) error {
@@ -263,6 +294,7 @@ func (ji *JobInfo) DeleteTaskInfo(ti *TaskInfo) error {
 		delete(ji.Tasks, task.UID)
 
 		ji.deleteTaskIndex(task)
+		ji.Generation++
 		return nil
 	}
 
@@ -291,6 +323,10 @@ func (ji *JobInfo) Clone() *JobInfo {
 		Tasks:           tasksMap{},
 	}
 
+	if ji.MinResources != nil {
+		info.MinResources = ji.MinResources.Clone()
+	}
+
 	ji.CreationTimestamp.DeepCopyInto(&info.CreationTimestamp)
 
 	for k, v := range ji.NodeSelector {
@@ -300,6 +336,7 @@ func (ji *JobInfo) Clone() *JobInfo {
 	for _, task := range ji.Tasks {
 		info.AddTaskInfo(task.Clone())
 	}
+	info.Generation = ji.Generation
 
 	return info
 }
@@ -333,8 +370,10 @@ func (ji *JobInfo) FitError() string {
 		if v.Get(v1.ResourceMemory) < 0 {
 			reasons["memory"]++
 		}
-		if v.Get(GPUResourceName) < 0 {
-			reasons["GPU"]++
+		for rName, rQuant := range v.ScalarResources {
+			if rQuant < 0 {
+				reasons[string(rName)]++
+			}
 		}
 	}
 
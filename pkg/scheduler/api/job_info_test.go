@@ -81,6 +81,7 @@ func TestAddTaskInfo(t *testing.T) {
 				},
 				NodeSelector:  make(map[string]string),
 				NodesFitDelta: make(NodeResourceMap),
+				Generation:    4,
 			},
 		},
 	}
@@ -147,6 +148,7 @@ func TestDeleteTaskInfo(t *testing.T) {
 				},
 				NodeSelector:  make(map[string]string),
 				NodesFitDelta: make(NodeResourceMap),
+				Generation:    4,
 			},
 		},
 		{
@@ -172,6 +174,7 @@ func TestDeleteTaskInfo(t *testing.T) {
 				},
 				NodeSelector:  make(map[string]string),
 				NodesFitDelta: make(NodeResourceMap),
+				Generation:    4,
 			},
 		},
 	}
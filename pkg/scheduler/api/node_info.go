@@ -39,6 +39,10 @@ type NodeInfo struct {
 	Capability  *Resource
 
 	Tasks map[TaskID]*TaskInfo
+
+	// Generation is increased by every change of the node, so the unchanged
+	// node can be shared by snapshots.
+	Generation int64
 }
 
 func NewNodeInfo(node *v1.Node) *NodeInfo {
@@ -76,6 +80,7 @@ func (ni *NodeInfo) Clone() *NodeInfo {
 	for _, p := range ni.Tasks {
 		res.AddTask(p)
 	}
+	res.Generation = ni.Generation
 
 	return res
 }
@@ -96,6 +101,8 @@ func (ni *NodeInfo) SetNode(node *v1.Node) {
 		ni.Idle.Sub(task.Resreq)
 		ni.Used.Add(task.Resreq)
 	}
+
+	ni.Generation++
 }
 
 func (ni *NodeInfo) AddTask(task *TaskInfo) error {
@@ -124,6 +131,7 @@ func (ni *NodeInfo) AddTask(task *TaskInfo) error {
 	}
 
 	ni.Tasks[key] = ti
+	ni.Generation++
 
 	return nil
 }
@@ -152,6 +160,7 @@ func (ni *NodeInfo) RemoveTask(ti *TaskInfo) error {
 	}
 
 	delete(ni.Tasks, key)
+	ni.Generation++
 
 	return nil
 }
@@ -60,6 +60,7 @@ func TestNodeInfo_AddPod(t *testing.T) {
 					"c1/p1": NewTaskInfo(case01_pod1),
 					"c1/p2": NewTaskInfo(case01_pod2),
 				},
+				Generation: 2,
 			},
 		},
 	}
@@ -110,6 +111,7 @@ func TestNodeInfo_RemovePod(t *testing.T) {
 					"c1/p1": NewTaskInfo(case01_pod1),
 					"c1/p3": NewTaskInfo(case01_pod3),
 				},
+				Generation: 4,
 			},
 		},
 	}
@@ -17,9 +17,20 @@ limitations under the License.
 package api
 
 import (
+	"encoding/json"
+	"strings"
+
+	"github.com/golang/glog"
+
 	"k8s.io/api/core/v1"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 )
 
+// PodRequestFn returns the resources requested by pod when it's running and the
+// resources required to start it, which are Resreq and InitResreq of TaskInfo.
+type PodRequestFn func(pod *v1.Pod) (*Resource, *Resource)
+
 // Refer k8s.io/kubernetes/pkg/scheduler/algorithm/predicates/predicates.go#GetResourceRequest.
 //
 // GetResourceRequest returns a *Resource that covers the largest width in each resource dimension.
@@ -69,3 +80,89 @@ func GetPodResourceWithoutInitContainers(pod *v1.Pod) *Resource {
 
 	return result
 }
+
+// DefaultPodRequest is the default PodRequestFn. Besides the requests of containers,
+// it accounts the overhead of pod and the init containers running as sidecars, and
+// ignores the excluded containers; they're specified by the annotations of pod.
+//
+// Example:
+//
+// Pod:
+//   Annotations
+//     scheduling.k8s.io/pod-overhead: {"cpu": "1"}
+//     scheduling.k8s.io/sidecar-init-containers: IC1
+//   InitContainers
+//     IC1:
+//       CPU: 1
+//     IC2:
+//       CPU: 4
+//   Containers
+//     C1:
+//       CPU: 2
+//
+// Result: Resreq CPU: 4, InitResreq CPU: 6
+func DefaultPodRequest(pod *v1.Pod) (*Resource, *Resource) {
+	excluded := annotatedContainers(pod, v1alpha1.ExcludedContainersAnnotationKey)
+	sidecars := annotatedContainers(pod, v1alpha1.SidecarInitContainersAnnotationKey)
+
+	req := EmptyResource()
+	for _, container := range pod.Spec.Containers {
+		if excluded[container.Name] {
+			continue
+		}
+		req.Add(NewResource(container.Resources.Requests))
+	}
+
+	// Init containers run sequentially, but the sidecars started before
+	// an init container keep running with it.
+	initResreq := EmptyResource()
+	started := EmptyResource()
+	for _, container := range pod.Spec.InitContainers {
+		if excluded[container.Name] {
+			continue
+		}
+		if sidecars[container.Name] {
+			started.Add(NewResource(container.Resources.Requests))
+			continue
+		}
+		initResreq.SetMaxResource(started.Clone().Add(NewResource(container.Resources.Requests)))
+	}
+	req.Add(started)
+	initResreq.SetMaxResource(req)
+
+	if overhead := podOverhead(pod); overhead != nil {
+		req.Add(overhead)
+		initResreq.Add(overhead)
+	}
+
+	return req, initResreq
+}
+
+// podOverhead returns the overhead of pod in its annotation; PodSpec.Overhead is
+// not supported by the vendored k8s.io/api yet.
+func podOverhead(pod *v1.Pod) *Resource {
+	value, found := pod.Annotations[v1alpha1.PodOverheadAnnotationKey]
+	if !found {
+		return nil
+	}
+
+	overhead := v1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &overhead); err != nil {
+		glog.Warningf("Failed to parse overhead <%s> of pod <%s/%s>, ignore it: %v",
+			value, pod.Namespace, pod.Name, err)
+		return nil
+	}
+
+	return NewResource(overhead)
+}
+
+// annotatedContainers returns the names of containers in the annotation of pod.
+func annotatedContainers(pod *v1.Pod, key string) map[string]bool {
+	names := map[string]bool{}
+	for _, name := range strings.Split(pod.Annotations[key], ",") {
+		if name = strings.TrimSpace(name); len(name) != 0 {
+			names[name] = true
+		}
+	}
+	return names
+}
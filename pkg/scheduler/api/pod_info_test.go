@@ -21,6 +21,9 @@ import (
 	"testing"
 
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 )
 
 func TestGetPodResourceRequest(t *testing.T) {
@@ -160,3 +163,112 @@ func TestGetPodResourceWithoutInitContainers(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultPodRequest(t *testing.T) {
+	tests := []struct {
+		name               string
+		pod                *v1.Pod
+		expectedResreq     *Resource
+		expectedInitResreq *Resource
+	}{
+		{
+			name: "get resource for pod without annotations",
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{
+						{
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("4000m", "1G"),
+							},
+						},
+					},
+					Containers: []v1.Container{
+						{
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("2000m", "2G"),
+							},
+						},
+					},
+				},
+			},
+			expectedResreq:     NewResource(buildResourceList("2000m", "2G")),
+			expectedInitResreq: NewResource(buildResourceList("4000m", "2G")),
+		},
+		{
+			name: "get resource for pod with overhead",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						v1alpha1.PodOverheadAnnotationKey: `{"cpu": "1", "memory": "1G"}`,
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("2000m", "2G"),
+							},
+						},
+					},
+				},
+			},
+			expectedResreq:     NewResource(buildResourceList("3000m", "3G")),
+			expectedInitResreq: NewResource(buildResourceList("3000m", "3G")),
+		},
+		{
+			name: "get resource for pod with sidecar and excluded containers",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						v1alpha1.SidecarInitContainersAnnotationKey: "ic1",
+						v1alpha1.ExcludedContainersAnnotationKey:    "c2",
+					},
+				},
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{
+						{
+							Name: "ic1",
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("1000m", "1G"),
+							},
+						},
+						{
+							Name: "ic2",
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("4000m", "1G"),
+							},
+						},
+					},
+					Containers: []v1.Container{
+						{
+							Name: "c1",
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("2000m", "1G"),
+							},
+						},
+						{
+							Name: "c2",
+							Resources: v1.ResourceRequirements{
+								Requests: buildResourceList("2000m", "1G"),
+							},
+						},
+					},
+				},
+			},
+			expectedResreq:     NewResource(buildResourceList("3000m", "2G")),
+			expectedInitResreq: NewResource(buildResourceList("5000m", "2G")),
+		},
+	}
+
+	for i, test := range tests {
+		req, initResreq := DefaultPodRequest(test.pod)
+		if !reflect.DeepEqual(req, test.expectedResreq) {
+			t.Errorf("case %d(%s) failed: \n expected resreq %v, \n got: %v \n",
+				i, test.name, test.expectedResreq, req)
+		}
+		if !reflect.DeepEqual(initResreq, test.expectedInitResreq) {
+			t.Errorf("case %d(%s) failed: \n expected init resreq %v, \n got: %v \n",
+				i, test.name, test.expectedInitResreq, initResreq)
+		}
+	}
+}
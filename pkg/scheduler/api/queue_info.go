@@ -30,25 +30,46 @@ type QueueInfo struct {
 
 	Weight int32
 
+	// Parent is the ID of the parent queue; it's empty for root queues.
+	Parent QueueID
+
+	// Capability is the upper limit of resources of the Queue;
+	// it's nil if no capability is defined.
+	Capability *Resource
+
 	Queue *arbcorev1.Queue
 }
 
 func NewQueueInfo(queue *arbcorev1.Queue) *QueueInfo {
-	return &QueueInfo{
+	qi := &QueueInfo{
 		UID:  QueueID(queue.Name),
 		Name: queue.Name,
 
 		Weight: queue.Spec.Weight,
+		Parent: QueueID(queue.Spec.Parent),
 
 		Queue: queue,
 	}
+
+	if len(queue.Spec.Capability) != 0 {
+		qi.Capability = NewResource(queue.Spec.Capability)
+	}
+
+	return qi
 }
 
 func (q *QueueInfo) Clone() *QueueInfo {
-	return &QueueInfo{
+	info := &QueueInfo{
 		UID:    q.UID,
 		Name:   q.Name,
 		Weight: q.Weight,
+		Parent: q.Parent,
 		Queue:  q.Queue,
 	}
+
+	if q.Capability != nil {
+		info.Capability = q.Capability.Clone()
+	}
+
+	return info
 }
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+)
+
+// ReservationID is the ID of Reservation, in the format of "namespace/name".
+type ReservationID string
+
+// ReservationInfo is the scheduler view of Reservation.
+type ReservationInfo struct {
+	UID       ReservationID
+	Name      string
+	Namespace string
+
+	// Owner is the ID of the Job which owns the reserved resources.
+	Owner JobID
+
+	// Resreq is the amount of resources to reserve.
+	Resreq *Resource
+
+	// NodeSelector selects the nodes on which resources are reserved.
+	NodeSelector labels.Selector
+
+	// Expiration is the time the reservation expires; it's zero if the
+	// reservation never expires.
+	Expiration time.Time
+
+	Reservation *v1alpha1.Reservation
+}
+
+// NewReservationInfo creates a ReservationInfo from Reservation.
+func NewReservationInfo(r *v1alpha1.Reservation) *ReservationInfo {
+	ri := &ReservationInfo{
+		UID:       ReservationID(fmt.Sprintf("%s/%s", r.Namespace, r.Name)),
+		Name:      r.Name,
+		Namespace: r.Namespace,
+		// Same as the ID of Job created from PodGroup.
+		Owner:        JobID(fmt.Sprintf("%s/%s", r.Namespace, r.Spec.PodGroup)),
+		Resreq:       NewResource(r.Spec.Resources),
+		NodeSelector: labels.SelectorFromSet(labels.Set(r.Spec.NodeSelector)),
+		Reservation:  r,
+	}
+
+	if r.Spec.TTLSeconds != nil {
+		ri.Expiration = r.CreationTimestamp.Add(time.Duration(*r.Spec.TTLSeconds) * time.Second)
+	}
+
+	return ri
+}
+
+// Expired returns whether the reservation is expired at the given time.
+func (ri *ReservationInfo) Expired(now time.Time) bool {
+	return !ri.Expiration.IsZero() && now.After(ri.Expiration)
+}
+
+// Clone is used to clone a ReservationInfo object.
+func (ri *ReservationInfo) Clone() *ReservationInfo {
+	return &ReservationInfo{
+		UID:          ri.UID,
+		Name:         ri.Name,
+		Namespace:    ri.Namespace,
+		Owner:        ri.Owner,
+		Resreq:       ri.Resreq.Clone(),
+		NodeSelector: ri.NodeSelector,
+		Expiration:   ri.Expiration,
+		Reservation:  ri.Reservation,
+	}
+}
+
+func (ri ReservationInfo) String() string {
+	return fmt.Sprintf("Reservation (%v): owner %v, resreq %v, selector %v",
+		ri.UID, ri.Owner, ri.Resreq, ri.NodeSelector)
+}
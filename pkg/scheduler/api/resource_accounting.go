@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync/atomic"
+
+	"k8s.io/api/core/v1"
+)
+
+// ResourceAccounting defines how resources are accounted in Resource.
+type ResourceAccounting struct {
+	// GPUResourceNames are the names of resources accounted as GPU; they're still
+	// fit by their own names.
+	GPUResourceNames map[v1.ResourceName]bool
+
+	// The minimal deltas of resources; the amounts of resource within
+	// the delta are regarded as equal.
+	MinMilliCPU float64
+	MinMemory   float64
+	MinMilliGPU float64
+	// MinMilliScalarResources is the minimal delta of the scalar resources
+	// which are not in MinMilliScalars.
+	MinMilliScalarResources float64
+	MinMilliScalars         map[v1.ResourceName]float64
+}
+
+// NewResourceAccounting returns the default ResourceAccounting.
+func NewResourceAccounting() *ResourceAccounting {
+	return &ResourceAccounting{
+		GPUResourceNames: map[v1.ResourceName]bool{
+			GPUResourceName: true,
+		},
+		MinMilliCPU:             10,
+		MinMemory:               10 * 1024 * 1024,
+		MinMilliGPU:             10,
+		MinMilliScalarResources: 10,
+		MinMilliScalars:         map[v1.ResourceName]float64{},
+	}
+}
+
+// isGPU returns whether the resource is accounted as GPU.
+func (ra *ResourceAccounting) isGPU(rn v1.ResourceName) bool {
+	return ra.GPUResourceNames[rn]
+}
+
+// minMilliScalar returns the minimal delta of the scalar resource; the delta of
+// GPU class applies to the resources accounted as GPU.
+func (ra *ResourceAccounting) minMilliScalar(rn v1.ResourceName) float64 {
+	if ra.isGPU(rn) {
+		return ra.MinMilliGPU
+	}
+	if min, found := ra.MinMilliScalars[rn]; found {
+		return min
+	}
+	return ra.MinMilliScalarResources
+}
+
+var accounting atomic.Value
+
+func init() {
+	accounting.Store(NewResourceAccounting())
+}
+
+// SetResourceAccounting sets how resources are accounted; it should be set
+// before any Resource is created, as the existing ones are not re-accounted.
+func SetResourceAccounting(ra *ResourceAccounting) {
+	accounting.Store(ra)
+}
+
+func resourceAccounting() *ResourceAccounting {
+	return accounting.Load().(*ResourceAccounting)
+}
@@ -19,14 +19,24 @@ package api
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type Resource struct {
 	MilliCPU float64
 	Memory   float64
+	// MilliGPU is the total of the resources accounted as GPU, which is only used
+	// to account the shares of GPU; the resources are fit by their own names in
+	// ScalarResources.
 	MilliGPU float64
+
+	// ScalarResources are the resources other than cpu and memory, e.g. GPU,
+	// ephemeral-storage, hugepages and extended resources, in milli unit.
+	ScalarResources map[v1.ResourceName]float64
+
 	// MaxTaskNum is only used by predicates; it should NOT
 	// be accounted in other operators, e.g. Add.
 	MaxTaskNum int
@@ -48,44 +58,94 @@ func (r *Resource) Clone() *Resource {
 		MilliGPU:   r.MilliGPU,
 		MaxTaskNum: r.MaxTaskNum,
 	}
+
+	if r.ScalarResources != nil {
+		clone.ScalarResources = make(map[v1.ResourceName]float64, len(r.ScalarResources))
+		for rName, rQuant := range r.ScalarResources {
+			clone.ScalarResources[rName] = rQuant
+		}
+	}
 	return clone
 }
 
-var minMilliCPU float64 = 10
-var minMilliGPU float64 = 10
-var minMemory float64 = 10 * 1024 * 1024
-
 func NewResource(rl v1.ResourceList) *Resource {
+	ra := resourceAccounting()
+
 	r := EmptyResource()
 	for rName, rQuant := range rl {
-		switch rName {
-		case v1.ResourceCPU:
+		switch {
+		case rName == v1.ResourceCPU:
 			r.MilliCPU += float64(rQuant.MilliValue())
-		case v1.ResourceMemory:
+		case rName == v1.ResourceMemory:
 			r.Memory += float64(rQuant.Value())
-		case v1.ResourcePods:
+		case rName == v1.ResourcePods:
 			r.MaxTaskNum += int(rQuant.Value())
-		case GPUResourceName:
+		case ra.isGPU(rName):
 			r.MilliGPU += float64(rQuant.MilliValue())
+			r.AddScalar(rName, float64(rQuant.MilliValue()))
+		default:
+			r.AddScalar(rName, float64(rQuant.MilliValue()))
 		}
 	}
 	return r
 }
 
+// AddScalar adds a resource by a scalar value of this resource.
+func (r *Resource) AddScalar(name v1.ResourceName, quantity float64) {
+	r.SetScalar(name, r.ScalarResources[name]+quantity)
+}
+
+// SetScalar sets a resource by a scalar value of this resource.
+func (r *Resource) SetScalar(name v1.ResourceName, quantity float64) {
+	if r.ScalarResources == nil {
+		r.ScalarResources = map[v1.ResourceName]float64{}
+	}
+	r.ScalarResources[name] = quantity
+}
+
+// ResourceList returns the non-zero resources as v1.ResourceList.
+func (r *Resource) ResourceList() v1.ResourceList {
+	rl := v1.ResourceList{}
+	if r.MilliCPU > 0 {
+		rl[v1.ResourceCPU] = *resource.NewMilliQuantity(int64(r.MilliCPU), resource.DecimalSI)
+	}
+	if r.Memory > 0 {
+		rl[v1.ResourceMemory] = *resource.NewQuantity(int64(r.Memory), resource.BinarySI)
+	}
+	for rName, rQuant := range r.ScalarResources {
+		if rQuant > 0 {
+			rl[rName] = *resource.NewMilliQuantity(int64(rQuant), resource.DecimalSI)
+		}
+	}
+	return rl
+}
+
 func (r *Resource) IsEmpty() bool {
-	return r.MilliCPU < minMilliCPU && r.Memory < minMemory && r.MilliGPU < minMilliGPU
+	ra := resourceAccounting()
+
+	if r.MilliCPU >= ra.MinMilliCPU || r.Memory >= ra.MinMemory || r.MilliGPU >= ra.MinMilliGPU {
+		return false
+	}
+
+	for rName, rQuant := range r.ScalarResources {
+		if rQuant >= ra.minMilliScalar(rName) {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (r *Resource) IsZero(rn v1.ResourceName) bool {
-	switch rn {
-	case v1.ResourceCPU:
-		return r.MilliCPU < minMilliCPU
-	case v1.ResourceMemory:
-		return r.Memory < minMemory
-	case GPUResourceName:
-		return r.MilliGPU < minMilliGPU
+	ra := resourceAccounting()
+
+	switch {
+	case rn == v1.ResourceCPU:
+		return r.MilliCPU < ra.MinMilliCPU
+	case rn == v1.ResourceMemory:
+		return r.Memory < ra.MinMemory
 	default:
-		panic("unknown resource")
+		return r.ScalarResources[rn] < ra.minMilliScalar(rn)
 	}
 }
 
@@ -93,6 +153,10 @@ func (r *Resource) Add(rr *Resource) *Resource {
 	r.MilliCPU += rr.MilliCPU
 	r.Memory += rr.Memory
 	r.MilliGPU += rr.MilliGPU
+
+	for rName, rQuant := range rr.ScalarResources {
+		r.AddScalar(rName, rQuant)
+	}
 	return r
 }
 
@@ -102,6 +166,10 @@ func (r *Resource) Sub(rr *Resource) *Resource {
 		r.MilliCPU -= rr.MilliCPU
 		r.Memory -= rr.Memory
 		r.MilliGPU -= rr.MilliGPU
+
+		for rName, rQuant := range rr.ScalarResources {
+			r.AddScalar(rName, -rQuant)
+		}
 		return r
 	}
 
@@ -124,6 +192,12 @@ func (r *Resource) SetMaxResource(rr *Resource) {
 	if rr.MilliGPU > r.MilliGPU {
 		r.MilliGPU = rr.MilliGPU
 	}
+
+	for rName, rQuant := range rr.ScalarResources {
+		if rQuant > r.ScalarResources[rName] {
+			r.SetScalar(rName, rQuant)
+		}
+	}
 }
 
 //Computes the delta between a resource oject representing available
@@ -131,16 +205,24 @@ func (r *Resource) SetMaxResource(rr *Resource) {
 //field that is less than 0 after the operation represents an
 //insufficient resource.
 func (r *Resource) FitDelta(rr *Resource) *Resource {
+	ra := resourceAccounting()
+
 	if rr.MilliCPU > 0 {
-		r.MilliCPU -= rr.MilliCPU + minMilliCPU
+		r.MilliCPU -= rr.MilliCPU + ra.MinMilliCPU
 	}
 
 	if rr.Memory > 0 {
-		r.Memory -= rr.Memory + minMemory
+		r.Memory -= rr.Memory + ra.MinMemory
 	}
 
 	if rr.MilliGPU > 0 {
-		r.MilliGPU -= rr.MilliGPU + minMilliGPU
+		r.MilliGPU -= rr.MilliGPU + ra.MinMilliGPU
+	}
+
+	for rName, rQuant := range rr.ScalarResources {
+		if rQuant > 0 {
+			r.AddScalar(rName, -(rQuant + ra.minMilliScalar(rName)))
+		}
 	}
 	return r
 }
@@ -149,37 +231,119 @@ func (r *Resource) Multi(ratio float64) *Resource {
 	r.MilliCPU = r.MilliCPU * ratio
 	r.Memory = r.Memory * ratio
 	r.MilliGPU = r.MilliGPU * ratio
+
+	for rName, rQuant := range r.ScalarResources {
+		r.ScalarResources[rName] = rQuant * ratio
+	}
 	return r
 }
 
+// scalarNames returns the names of scalar resources in either of the two Resources.
+func scalarNames(l, r *Resource) map[v1.ResourceName]bool {
+	names := map[v1.ResourceName]bool{}
+	for rName := range l.ScalarResources {
+		names[rName] = true
+	}
+	for rName := range r.ScalarResources {
+		names[rName] = true
+	}
+	return names
+}
+
 func (r *Resource) Less(rr *Resource) bool {
-	return r.MilliCPU < rr.MilliCPU && r.Memory < rr.Memory && r.MilliGPU < rr.MilliGPU
+	if !(r.MilliCPU < rr.MilliCPU && r.Memory < rr.Memory && r.MilliGPU < rr.MilliGPU) {
+		return false
+	}
+
+	for rName := range scalarNames(r, rr) {
+		if !(r.ScalarResources[rName] < rr.ScalarResources[rName]) {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (r *Resource) LessEqual(rr *Resource) bool {
-	return (r.MilliCPU < rr.MilliCPU || math.Abs(rr.MilliCPU-r.MilliCPU) < minMilliCPU) &&
-		(r.Memory < rr.Memory || math.Abs(rr.Memory-r.Memory) < minMemory) &&
-		(r.MilliGPU < rr.MilliGPU || math.Abs(rr.MilliGPU-r.MilliGPU) < minMilliGPU)
+	ra := resourceAccounting()
+
+	if !((r.MilliCPU < rr.MilliCPU || math.Abs(rr.MilliCPU-r.MilliCPU) < ra.MinMilliCPU) &&
+		(r.Memory < rr.Memory || math.Abs(rr.Memory-r.Memory) < ra.MinMemory) &&
+		(r.MilliGPU < rr.MilliGPU || math.Abs(rr.MilliGPU-r.MilliGPU) < ra.MinMilliGPU)) {
+		return false
+	}
+
+	for rName := range scalarNames(r, rr) {
+		lQuant, rQuant := r.ScalarResources[rName], rr.ScalarResources[rName]
+		if !(lQuant < rQuant || math.Abs(rQuant-lQuant) < ra.minMilliScalar(rName)) {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (r *Resource) String() string {
-	return fmt.Sprintf("cpu %0.2f, memory %0.2f, GPU %0.2f",
+	str := fmt.Sprintf("cpu %0.2f, memory %0.2f, GPU %0.2f",
 		r.MilliCPU, r.Memory, r.MilliGPU)
+
+	for _, rName := range r.scalarResourceNames() {
+		str = fmt.Sprintf("%s, %s %0.2f", str, rName, r.ScalarResources[rName])
+	}
+
+	return str
 }
 
+// Get returns the amount of resource; the total of GPU class is returned for
+// GPUResourceName, as it's the name of GPU class in ResourceNames.
 func (r *Resource) Get(rn v1.ResourceName) float64 {
-	switch rn {
-	case v1.ResourceCPU:
+	switch {
+	case rn == v1.ResourceCPU:
 		return r.MilliCPU
-	case v1.ResourceMemory:
+	case rn == v1.ResourceMemory:
 		return r.Memory
-	case GPUResourceName:
+	case rn == GPUResourceName:
 		return r.MilliGPU
 	default:
-		panic("not support resource.")
+		return r.ScalarResources[rn]
+	}
+}
+
+// SumGPU returns the total of the resources accounted as GPU in ScalarResources.
+func (r *Resource) SumGPU() float64 {
+	ra := resourceAccounting()
+
+	sum := float64(0)
+	for rName, rQuant := range r.ScalarResources {
+		if ra.isGPU(rName) {
+			sum += rQuant
+		}
+	}
+	return sum
+}
+
+// scalarResourceNames returns the names of scalar resources in order.
+func (r *Resource) scalarResourceNames() []v1.ResourceName {
+	names := make([]v1.ResourceName, 0, len(r.ScalarResources))
+	for rName := range r.ScalarResources {
+		names = append(names, rName)
 	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
 }
 
-func ResourceNames() []v1.ResourceName {
-	return []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, GPUResourceName}
+// ResourceNames returns the names of cpu, memory, GPU class and the scalar resources
+// not accounted as GPU of Resource, which are the dimensions of shares.
+func (r *Resource) ResourceNames() []v1.ResourceName {
+	ra := resourceAccounting()
+
+	names := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, GPUResourceName}
+	for _, rName := range r.scalarResourceNames() {
+		if !ra.isGPU(rName) {
+			names = append(names, rName)
+		}
+	}
+	return names
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const amdGPUResourceName = "amd.com/gpu"
+
+func buildResourceListWithScalar(cpu string, memory string, scalar string) v1.ResourceList {
+	rl := buildResourceList(cpu, memory)
+	rl[amdGPUResourceName] = resource.MustParse(scalar)
+	return rl
+}
+
+func TestNewResourceWithScalar(t *testing.T) {
+	r := NewResource(buildResourceListWithScalar("1", "1G", "2"))
+
+	expected := &Resource{
+		MilliCPU:        1000,
+		Memory:          1000000000,
+		ScalarResources: map[v1.ResourceName]float64{amdGPUResourceName: 2000},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("expected: %#v, got: %#v", expected, r)
+	}
+
+	rl := r.ResourceList()
+	if q := rl[amdGPUResourceName]; q.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("expected %s in resource list, got: %v", amdGPUResourceName, rl)
+	}
+}
+
+func TestResourceWithScalar(t *testing.T) {
+	idle := NewResource(buildResourceListWithScalar("4", "4G", "1"))
+	req := NewResource(buildResourceListWithScalar("1", "1G", "2"))
+
+	if req.LessEqual(idle) {
+		t.Errorf("expected <%v> not to fit in <%v>", req, idle)
+	}
+
+	total := idle.Clone().Add(req)
+	if total.Get(amdGPUResourceName) != 3000 {
+		t.Errorf("expected 3000 %s, got <%v>", amdGPUResourceName, total)
+	}
+
+	if !req.LessEqual(total) {
+		t.Errorf("expected <%v> to fit in <%v>", req, total)
+	}
+
+	total.Sub(req)
+	if !reflect.DeepEqual(total, idle) {
+		t.Errorf("expected: <%v>, got: <%v>", idle, total)
+	}
+
+	delta := idle.Clone().FitDelta(req)
+	if delta.Get(amdGPUResourceName) >= 0 {
+		t.Errorf("expected insufficient %s in <%v>", amdGPUResourceName, delta)
+	}
+
+	names := req.ResourceNames()
+	if names[len(names)-1] != amdGPUResourceName {
+		t.Errorf("expected %s in resource names, got: %v", amdGPUResourceName, names)
+	}
+}
+
+func TestNewResourceWithGPUClass(t *testing.T) {
+	ra := NewResourceAccounting()
+	ra.GPUResourceNames[amdGPUResourceName] = true
+	ra.MinMilliGPU = 100
+	SetResourceAccounting(ra)
+	defer SetResourceAccounting(NewResourceAccounting())
+
+	r := NewResource(buildResourceListWithScalar("1", "1G", "2"))
+
+	expected := &Resource{
+		MilliCPU:        1000,
+		Memory:          1000000000,
+		MilliGPU:        2000,
+		ScalarResources: map[v1.ResourceName]float64{amdGPUResourceName: 2000},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("expected: %#v, got: %#v", expected, r)
+	}
+
+	if r.Get(GPUResourceName) != 2000 {
+		t.Errorf("expected 2000 GPU, got <%v>", r)
+	}
+
+	less := &Resource{
+		MilliCPU:        1000,
+		Memory:          1000000000,
+		MilliGPU:        1950,
+		ScalarResources: map[v1.ResourceName]float64{amdGPUResourceName: 1950},
+	}
+	if !r.LessEqual(less) {
+		t.Errorf("expected the delta of GPU within %v to be ignored", ra.MinMilliGPU)
+	}
+
+	// The resources of GPU class are fit by their own names.
+	idle := NewResource(v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("4G"),
+		GPUResourceName:   resource.MustParse("4"),
+	})
+	if r.LessEqual(idle) {
+		t.Errorf("expected <%v> not to fit in <%v>", r, idle)
+	}
+
+	rl := idle.Clone().Add(r).ResourceList()
+	if q := rl[GPUResourceName]; q.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("expected 4 %s in resource list, got: %v", GPUResourceName, rl)
+	}
+	if q := rl[amdGPUResourceName]; q.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("expected 2 %s in resource list, got: %v", amdGPUResourceName, rl)
+	}
+
+	names := r.ResourceNames()
+	if names[len(names)-1] != GPUResourceName {
+		t.Errorf("expected resources of GPU class to be accounted as GPU, got: %v", names)
+	}
+}
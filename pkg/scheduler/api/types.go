@@ -16,6 +16,10 @@ limitations under the License.
 
 package api
 
+import (
+	"time"
+)
+
 // TaskStatus defines the status of a task/pod.
 type TaskStatus int
 
@@ -97,11 +101,78 @@ type ValidateResult struct {
 
 type ValidateExFn func(interface{}) *ValidateResult
 
-// PredicateFn is the func declaration used to predicate node for task.
+// NotifyFn is the func declaration used to notify plugins of object's status change.
+type NotifyFn func(interface{})
+
+// AllocatableFn is the func declaration used to check whether the task can be allocated
+// resources in the queue, e.g. within the capability of queue.
+type AllocatableFn func(*QueueInfo, *TaskInfo) bool
+
+// PredicateFn is the func declaration used to predicate node for task; it's
+// called concurrently for different nodes.
 type PredicateFn func(*TaskInfo, *NodeInfo) error
 
 // EvictableFn is the func declaration used to evict tasks.
 type EvictableFn func(*TaskInfo, []*TaskInfo) []*TaskInfo
 
-// NodeOrderFn is the func declaration used to get priority score for a node for a particular task.
+// NodeOrderFn is the func declaration used to get priority score for a node for a particular task;
+// it's called concurrently for different nodes.
 type NodeOrderFn func(*TaskInfo, *NodeInfo) (int, error)
+
+// MaxNodeScore is the maximum score of node given by a plugin, after the scores of plugin
+// are normalized.
+const MaxNodeScore float64 = 100
+
+// NodeMapFn is the func declaration used to score a node for a task by a plugin; it's
+// called concurrently for different nodes.
+type NodeMapFn func(*TaskInfo, *NodeInfo) (float64, error)
+
+// NodeReduceFn is the func declaration used to adjust the scores of feasible nodes for a task
+// by a plugin, indexed by node name, before the scores are normalized.
+type NodeReduceFn func(*TaskInfo, map[string]float64) error
+
+// NodeOrderMapFn is the func declaration used to score a node for a task by plugins, indexed
+// by plugin name; it's called concurrently for different nodes.
+type NodeOrderMapFn func(*TaskInfo, *NodeInfo) (map[string]float64, error)
+
+// NodeOrderReduceFn is the func declaration used to get the total scores of nodes for a task,
+// indexed by node name, from the scores of nodes by plugins.
+type NodeOrderReduceFn func(*TaskInfo, []*NodeInfo, map[string]map[string]float64) (map[string]float64, error)
+
+// PreFilterFn is the func declaration used to prepare the state of task before its nodes are
+// predicated; an error means the task can not be placed on any node.
+type PreFilterFn func(*TaskInfo) error
+
+// PostFilterFn is the func declaration used to handle the feasible nodes of task after predicates.
+type PostFilterFn func(*TaskInfo, []*NodeInfo)
+
+// ReserveFn is the func declaration used to reserve the resources out of cluster,
+// e.g. licences, for the task on the host before it's bound.
+type ReserveFn func(*TaskInfo, string) error
+
+// UnreserveFn is the func declaration used to release the resources reserved by ReserveFn,
+// if the task is not bound to the host.
+type UnreserveFn func(*TaskInfo, string)
+
+// PermitCode is the decision of PermitFn on the binding of task.
+type PermitCode int
+
+const (
+	// PermitAllow allows the task to be bound.
+	PermitAllow PermitCode = iota
+	// PermitWait delays the binding of task until it's allowed or rejected by plugin, or timeout.
+	PermitWait
+	// PermitReject rejects the binding of task.
+	PermitReject
+)
+
+// PermitResult is the result of PermitFn; nil result allows the task to be bound.
+type PermitResult struct {
+	Code PermitCode
+	// Timeout is the maximum time to wait for the decision of plugin, for PermitWait.
+	Timeout time.Duration
+	Message string
+}
+
+// PermitFn is the func declaration used to allow, delay or reject the binding of task.
+type PermitFn func(*TaskInfo) *PermitResult
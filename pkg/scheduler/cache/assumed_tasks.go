@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+
+	kbapi "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/metrics"
+)
+
+// assumedTask is a task assumed to be bound to the host, before the binding is
+// confirmed by API server.
+type assumedTask struct {
+	task     *kbapi.TaskInfo
+	hostname string
+	deadline time.Time
+}
+
+// assumeTask records the task assumed to be bound to the host; the assumption
+// expires after BindOptions.AssumedTTL.
+func (sc *SchedulerCache) assumeTask(task *kbapi.TaskInfo, hostname string) *assumedTask {
+	ttl := sc.BindOptions.AssumedTTL
+	if ttl <= 0 {
+		ttl = DefaultAssumedTTL
+	}
+
+	if sc.assumedTasks == nil {
+		sc.assumedTasks = make(map[kbapi.TaskID]*assumedTask)
+	}
+	assumed := &assumedTask{
+		task:     task,
+		hostname: hostname,
+		deadline: time.Now().Add(ttl),
+	}
+	sc.assumedTasks[task.UID] = assumed
+
+	return assumed
+}
+
+// cleanupAssumedTasks moves the expired assumed tasks back to Pending, and releases
+// their resources on hosts; the bind requests of them still queued are dropped.
+func (sc *SchedulerCache) cleanupAssumedTasks() {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	sc.expireAssumedTasks(time.Now())
+}
+
+func (sc *SchedulerCache) expireAssumedTasks(now time.Time) {
+	for uid, assumed := range sc.assumedTasks {
+		_, task, err := sc.findJobAndTask(assumed.task)
+		// The binding is confirmed, or the task is gone.
+		if err != nil || task.Status != kbapi.Binding || task.NodeName != assumed.hostname {
+			delete(sc.assumedTasks, uid)
+			continue
+		}
+
+		if now.Before(assumed.deadline) {
+			continue
+		}
+
+		glog.Warningf("Binding of Task <%v/%v> to <%v> is not confirmed before %v, expire it.",
+			task.Namespace, task.Name, assumed.hostname, assumed.deadline)
+
+		sc.rollbackBind(task, assumed.hostname)
+		sc.resyncTask(task)
+		delete(sc.assumedTasks, uid)
+
+		metrics.RegisterAssumedTaskExpiration()
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+
+	kbapi "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/metrics"
+)
+
+const (
+	// DefaultBindParallelism is the default number of workers binding tasks.
+	DefaultBindParallelism = 16
+	// DefaultBindQPS is the default QPS of binding requests to API server.
+	DefaultBindQPS = 50
+	// DefaultBindBurst is the default burst of binding requests to API server.
+	DefaultBindBurst = 100
+	// DefaultBindTimeout is the default timeout of binding requests to API server.
+	DefaultBindTimeout = 30 * time.Second
+	// DefaultAssumedTTL is the default time for tasks to stay in Binding, before
+	// they're expired and moved back to Pending.
+	DefaultAssumedTTL = 5 * time.Minute
+
+	// assumedTasksCleanupPeriod is the period to clean up the expired assumed tasks.
+	assumedTasksCleanupPeriod = 10 * time.Second
+
+	// bindResultsSize is the buffer size of bind results.
+	bindResultsSize = 1024
+)
+
+// BindOptions configures the workers which bind tasks to hosts.
+type BindOptions struct {
+	// Parallelism is the number of workers binding tasks.
+	Parallelism int
+	// QPS is the maximum QPS of binding requests to API server.
+	QPS float32
+	// Burst is the maximum burst of binding requests to API server.
+	Burst int
+	// Timeout is the timeout of binding requests to API server.
+	Timeout time.Duration
+	// AssumedTTL is the time for tasks to stay in Binding before the binding is
+	// confirmed by API server; the tasks are moved back to Pending after that.
+	AssumedTTL time.Duration
+}
+
+// DefaultBindOptions returns the default BindOptions.
+func DefaultBindOptions() BindOptions {
+	return BindOptions{
+		Parallelism: DefaultBindParallelism,
+		QPS:         DefaultBindQPS,
+		Burst:       DefaultBindBurst,
+		Timeout:     DefaultBindTimeout,
+		AssumedTTL:  DefaultAssumedTTL,
+	}
+}
+
+// bindRequest is a request to bind the pod of task to host.
+type bindRequest struct {
+	task     *kbapi.TaskInfo
+	pod      *v1.Pod
+	hostname string
+	// assumed is the assumption of the task made with the request; the request is
+	// stale once the assumption is expired or replaced.
+	assumed *assumedTask
+}
+
+// bindResult is the result of bindRequest.
+type bindResult struct {
+	*bindRequest
+	err      error
+	duration time.Duration
+}
+
+// bindWorkers binds tasks by a limited number of workers at a limited rate; the
+// results are sent back to cache by channel.
+type bindWorkers struct {
+	once sync.Once
+
+	requests workqueue.Interface
+	results  chan *bindResult
+	limiter  flowcontrol.RateLimiter
+}
+
+// startBindWorkers starts the workers of cache; it only takes effect once, and
+// the default options are used if they're not set.
+func (sc *SchedulerCache) startBindWorkers(stopCh <-chan struct{}) {
+	sc.bindWorkers.once.Do(func() {
+		opts := sc.BindOptions
+		if opts.Parallelism <= 0 {
+			opts.Parallelism = DefaultBindParallelism
+		}
+		if opts.QPS <= 0 {
+			opts.QPS = DefaultBindQPS
+		}
+		if opts.Burst <= 0 {
+			opts.Burst = DefaultBindBurst
+		}
+
+		sc.bindWorkers.requests = workqueue.New()
+		sc.bindWorkers.results = make(chan *bindResult, bindResultsSize)
+		sc.bindWorkers.limiter = flowcontrol.NewTokenBucketRateLimiter(opts.QPS, opts.Burst)
+
+		glog.V(3).Infof("Starting %d bind workers with QPS %v and burst %d",
+			opts.Parallelism, opts.QPS, opts.Burst)
+
+		for i := 0; i < opts.Parallelism; i++ {
+			go wait.Until(sc.processBindRequest, 0, stopCh)
+		}
+		go wait.Until(sc.processBindResults, 0, stopCh)
+
+		go func() {
+			<-stopCh
+			sc.bindWorkers.requests.ShutDown()
+		}()
+	})
+}
+
+// enqueueBind adds a bind request; it never blocks, so it's safe to call with the lock of cache.
+func (sc *SchedulerCache) enqueueBind(task *kbapi.TaskInfo, hostname string, assumed *assumedTask) {
+	sc.startBindWorkers(wait.NeverStop)
+
+	sc.bindWorkers.requests.Add(&bindRequest{
+		task:     task,
+		pod:      task.Pod,
+		hostname: hostname,
+		assumed:  assumed,
+	})
+}
+
+// isStaleBindRequest returns whether the assumption of request is expired or replaced,
+// e.g. the task was moved back to Pending and allocated again while the request
+// was queued; stale requests are dropped without binding.
+func (sc *SchedulerCache) isStaleBindRequest(req *bindRequest) bool {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	return sc.assumedTasks[req.task.UID] != req.assumed
+}
+
+func (sc *SchedulerCache) processBindRequest() {
+	for {
+		obj, shutdown := sc.bindWorkers.requests.Get()
+		if shutdown {
+			return
+		}
+
+		req := obj.(*bindRequest)
+		if sc.isStaleBindRequest(req) {
+			glog.V(3).Infof("Drop stale binding of Task <%v/%v> to <%v>",
+				req.pod.Namespace, req.pod.Name, req.hostname)
+			sc.bindWorkers.requests.Done(obj)
+			continue
+		}
+
+		sc.bindWorkers.limiter.Accept()
+
+		start := time.Now()
+		err := sc.Binder.Bind(req.pod, req.hostname)
+		sc.bindWorkers.requests.Done(obj)
+
+		sc.bindWorkers.results <- &bindResult{
+			bindRequest: req,
+			err:         err,
+			duration:    metrics.Duration(start),
+		}
+	}
+}
+
+func (sc *SchedulerCache) processBindResults() {
+	for result := range sc.bindWorkers.results {
+		sc.processBindResult(result)
+	}
+}
+
+func (sc *SchedulerCache) processBindResult(result *bindResult) {
+	p := result.pod
+
+	if result.err == nil {
+		metrics.UpdateBindResult(metrics.BindSuccess, result.duration)
+		sc.Recorder.Eventf(p, v1.EventTypeNormal, "Scheduled", "Successfully assigned %v/%v to %v",
+			p.Namespace, p.Name, result.hostname)
+		return
+	}
+
+	metrics.UpdateBindResult(metrics.BindError, result.duration)
+	glog.Errorf("Failed to bind Task <%v/%v> to <%v>: %v",
+		p.Namespace, p.Name, result.hostname, result.err)
+	sc.Recorder.Eventf(p, v1.EventTypeWarning, "FailedScheduling", "Binding rejected: %v", result.err)
+
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	sc.rollbackBind(result.task, result.hostname)
+	sc.resyncTask(result.task)
+}
+
+// rollbackBind moves the task back to Pending and releases its resources on the host,
+// if the task is still binding to the host.
+func (sc *SchedulerCache) rollbackBind(taskInfo *kbapi.TaskInfo, hostname string) {
+	job, task, err := sc.findJobAndTask(taskInfo)
+	if err != nil {
+		glog.V(3).Infof("Ignore rollback of Task <%v/%v>: %v", taskInfo.Namespace, taskInfo.Name, err)
+		return
+	}
+
+	if task.Status != kbapi.Binding || task.NodeName != hostname {
+		return
+	}
+
+	if node, found := sc.Nodes[hostname]; found {
+		if err := node.RemoveTask(task); err != nil {
+			glog.Errorf("Failed to remove Task <%v/%v> from <%v>: %v",
+				task.Namespace, task.Name, hostname, err)
+		}
+	}
+
+	if err := job.UpdateTaskStatus(task, kbapi.Pending); err != nil {
+		glog.Errorf("Failed to update status of Task <%v/%v> to Pending: %v",
+			task.Namespace, task.Name, err)
+	}
+	task.NodeName = ""
+}
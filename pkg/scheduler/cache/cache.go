@@ -45,6 +45,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/volumebinder"
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha2"
 	kbver "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
 	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/scheme"
 	kbschema "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/scheme"
@@ -63,8 +64,8 @@ func init() {
 }
 
 // New returns a Cache implementation.
-func New(config *rest.Config, schedulerName string, defaultQueue string) Cache {
-	return newSchedulerCache(config, schedulerName, defaultQueue)
+func New(config *rest.Config, schedulerName string, defaultQueue string, bindOpts BindOptions, evictOpts EvictOptions) Cache {
+	return newSchedulerCache(config, schedulerName, defaultQueue, bindOpts, evictOpts)
 }
 
 type SchedulerCache struct {
@@ -77,27 +78,46 @@ type SchedulerCache struct {
 	// schedulerName is the name for kube-batch scheduler
 	schedulerName string
 
-	podInformer      infov1.PodInformer
-	nodeInformer     infov1.NodeInformer
-	pdbInformer      policyv1.PodDisruptionBudgetInformer
-	nsInformer       infov1.NamespaceInformer
-	podGroupInformer kbinfov1.PodGroupInformer
-	queueInformer    kbinfov1.QueueInformer
-	pvInformer       infov1.PersistentVolumeInformer
-	pvcInformer      infov1.PersistentVolumeClaimInformer
-	scInformer       storagev1.StorageClassInformer
-	pcInformer       schedv1.PriorityClassInformer
+	podInformer         infov1.PodInformer
+	nodeInformer        infov1.NodeInformer
+	pdbInformer         policyv1.PodDisruptionBudgetInformer
+	nsInformer          infov1.NamespaceInformer
+	podGroupInformer    cache.SharedIndexInformer
+	queueInformer       cache.SharedIndexInformer
+	reservationInformer kbinfov1.ReservationInformer
+	pvInformer          infov1.PersistentVolumeInformer
+	pvcInformer         infov1.PersistentVolumeClaimInformer
+	scInformer          storagev1.StorageClassInformer
+	pcInformer          schedv1.PriorityClassInformer
 
 	Binder        Binder
 	Evictor       Evictor
 	StatusUpdater StatusUpdater
 	VolumeBinder  VolumeBinder
+	// BindOptions configures the workers binding tasks.
+	BindOptions BindOptions
+	bindWorkers bindWorkers
+	// assumedTasks is the tasks in Binding, indexed by task ID.
+	assumedTasks map[kbapi.TaskID]*assumedTask
+	// EvictOptions configures how the pods are evicted.
+	EvictOptions EvictOptions
+	evictWorkers evictWorkers
+	// refusedBudgets is the resource versions of PodDisruptionBudgets which refused
+	// evictions, indexed by namespace/name.
+	refusedBudgets map[string]string
+
+	// PodRequest calculates the resources requested by pods; the
+	// api.DefaultPodRequest is used if it's nil.
+	PodRequest kbapi.PodRequestFn
 
 	Recorder record.EventRecorder
 
 	Jobs                 map[kbapi.JobID]*kbapi.JobInfo
 	Nodes                map[string]*kbapi.NodeInfo
 	Queues               map[kbapi.QueueID]*kbapi.QueueInfo
+	Reservations         map[kbapi.ReservationID]*kbapi.ReservationInfo
+	// snapshots is the clones of nodes and jobs shared by snapshots.
+	snapshots snapshots
 	PriorityClasses      map[string]*v1beta1.PriorityClass
 	defaultPriorityClass *v1beta1.PriorityClass
 	defaultPriority      int32
@@ -126,12 +146,15 @@ func (db *defaultBinder) Bind(p *v1.Pod, hostname string) error {
 
 type defaultEvictor struct {
 	kubeclient *kubernetes.Clientset
+	opts       EvictOptions
 }
 
-func (de *defaultEvictor) Evict(p *v1.Pod) error {
+func (de *defaultEvictor) Evict(p *v1.Pod, reason string) error {
 	glog.V(3).Infof("Evicting pod %v/%v", p.Namespace, p.Name)
 
-	if err := de.kubeclient.CoreV1().Pods(p.Namespace).Delete(p.Name, nil); err != nil {
+	markDisruptionTarget(de.kubeclient, p, v1.ConditionTrue, PreemptionByKubeBatchReason, reason)
+
+	if err := de.kubeclient.CoreV1().Pods(p.Namespace).Delete(p.Name, de.opts.deleteOptions(p)); err != nil {
 		glog.Errorf("Failed to evict pod <%v/%v>: %#v", p.Namespace, p.Name, err)
 		return err
 	}
@@ -142,6 +165,8 @@ func (de *defaultEvictor) Evict(p *v1.Pod) error {
 type defaultStatusUpdater struct {
 	kubeclient *kubernetes.Clientset
 	kbclient   *kbver.Clientset
+	// apiVersion is the version of scheduling API used to update PodGroup and Queue.
+	apiVersion string
 }
 
 // Update pod with podCondition
@@ -155,7 +180,44 @@ func (su *defaultStatusUpdater) UpdatePodCondition(pod *v1.Pod, condition *v1.Po
 
 // Update pod with podCondition
 func (su *defaultStatusUpdater) UpdatePodGroup(pg *v1alpha1.PodGroup) (*v1alpha1.PodGroup, error) {
-	return su.kbclient.SchedulingV1alpha1().PodGroups(pg.Namespace).Update(pg)
+	if su.apiVersion != v1alpha2.GroupVersion {
+		return su.kbclient.SchedulingV1alpha1().PodGroups(pg.Namespace).Update(pg)
+	}
+
+	in := &v1alpha2.PodGroup{}
+	if err := v1alpha2.Convert_v1alpha1_PodGroup_To_v1alpha2_PodGroup(pg, in, nil); err != nil {
+		return nil, err
+	}
+	updated, err := su.kbclient.SchedulingV1alpha2().PodGroups(pg.Namespace).Update(in)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.PodGroup{}
+	if err := v1alpha2.Convert_v1alpha2_PodGroup_To_v1alpha1_PodGroup(updated, out, nil); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateQueueStatus updates the status of queue
+func (su *defaultStatusUpdater) UpdateQueueStatus(queue *v1alpha1.Queue) (*v1alpha1.Queue, error) {
+	if su.apiVersion != v1alpha2.GroupVersion {
+		return su.kbclient.SchedulingV1alpha1().Queues().UpdateStatus(queue)
+	}
+
+	in := &v1alpha2.Queue{}
+	if err := v1alpha2.Convert_v1alpha1_Queue_To_v1alpha2_Queue(queue, in, nil); err != nil {
+		return nil, err
+	}
+	updated, err := su.kbclient.SchedulingV1alpha2().Queues().UpdateStatus(in)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.Queue{}
+	if err := v1alpha2.Convert_v1alpha2_Queue_To_v1alpha1_Queue(updated, out, nil); err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 type defaultVolumeBinder struct {
@@ -180,11 +242,12 @@ func (dvb *defaultVolumeBinder) BindVolumes(task *api.TaskInfo) error {
 	return dvb.volumeBinder.Binder.BindPodVolumes(task.Pod)
 }
 
-func newSchedulerCache(config *rest.Config, schedulerName string, defaultQueue string) *SchedulerCache {
+func newSchedulerCache(config *rest.Config, schedulerName string, defaultQueue string, bindOpts BindOptions, evictOpts EvictOptions) *SchedulerCache {
 	sc := &SchedulerCache{
 		Jobs:            make(map[kbapi.JobID]*kbapi.JobInfo),
 		Nodes:           make(map[string]*kbapi.NodeInfo),
 		Queues:          make(map[kbapi.QueueID]*kbapi.QueueInfo),
+		Reservations:    make(map[kbapi.ReservationID]*kbapi.ReservationInfo),
 		PriorityClasses: make(map[string]*v1beta1.PriorityClass),
 		errTasks:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 		deletedJobs:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
@@ -192,6 +255,9 @@ func newSchedulerCache(config *rest.Config, schedulerName string, defaultQueue s
 		kbclient:        kbver.NewForConfigOrDie(config),
 		defaultQueue:    defaultQueue,
 		schedulerName:   schedulerName,
+		BindOptions:     bindOpts,
+		EvictOptions:    evictOpts,
+		assumedTasks:    make(map[kbapi.TaskID]*assumedTask),
 	}
 
 	// Prepare event clients.
@@ -199,17 +265,24 @@ func newSchedulerCache(config *rest.Config, schedulerName string, defaultQueue s
 	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: sc.kubeclient.CoreV1().Events("")})
 	sc.Recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "kube-batch"})
 
+	// Bind pods by a separate client, so hung binding requests are timed out.
+	bindConfig := rest.CopyConfig(config)
+	bindConfig.Timeout = bindOpts.Timeout
 	sc.Binder = &defaultBinder{
-		kubeclient: sc.kubeclient,
+		kubeclient: kubernetes.NewForConfigOrDie(bindConfig),
 	}
 
-	sc.Evictor = &defaultEvictor{
-		kubeclient: sc.kubeclient,
-	}
+	sc.Evictor = newEvictor(sc.kubeclient, evictOpts)
+
+	sc.PodRequest = kbapi.DefaultPodRequest
+
+	apiVersion := schedulingAPIVersion(sc.kbclient)
+	glog.V(3).Infof("Using version <%s> of scheduling API for PodGroup and Queue.", apiVersion)
 
 	sc.StatusUpdater = &defaultStatusUpdater{
 		kubeclient: sc.kubeclient,
 		kbclient:   sc.kbclient,
+		apiVersion: apiVersion,
 	}
 
 	informerFactory := informers.NewSharedInformerFactory(sc.kubeclient, 0)
@@ -276,36 +349,73 @@ func newSchedulerCache(config *rest.Config, schedulerName string, defaultQueue s
 	})
 
 	kbinformer := kbinfo.NewSharedInformerFactory(sc.kbclient, 0)
+	// create informers of either version; the objects of v1alpha2 are converted
+	// to v1alpha1 by event handlers.
+	if apiVersion == v1alpha2.GroupVersion {
+		sc.podGroupInformer = kbinformer.Scheduling().V1alpha2().PodGroups().Informer()
+		sc.queueInformer = kbinformer.Scheduling().V1alpha2().Queues().Informer()
+	} else {
+		sc.podGroupInformer = kbinformer.Scheduling().V1alpha1().PodGroups().Informer()
+		sc.queueInformer = kbinformer.Scheduling().V1alpha1().Queues().Informer()
+	}
+
 	// create informer for PodGroup information
-	sc.podGroupInformer = kbinformer.Scheduling().V1alpha1().PodGroups()
-	sc.podGroupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	sc.podGroupInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    sc.AddPodGroup,
 		UpdateFunc: sc.UpdatePodGroup,
 		DeleteFunc: sc.DeletePodGroup,
 	})
 
 	// create informer for Queue information
-	sc.queueInformer = kbinformer.Scheduling().V1alpha1().Queues()
-	sc.queueInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	sc.queueInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    sc.AddQueue,
 		UpdateFunc: sc.UpdateQueue,
 		DeleteFunc: sc.DeleteQueue,
 	})
 
+	// create informer for Reservation information
+	sc.reservationInformer = kbinformer.Scheduling().V1alpha1().Reservations()
+	sc.reservationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sc.AddReservation,
+		UpdateFunc: sc.UpdateReservation,
+		DeleteFunc: sc.DeleteReservation,
+	})
+
 	return sc
 }
 
+// schedulingAPIVersion returns v1alpha2 if it's served by the API server, otherwise v1alpha1.
+func schedulingAPIVersion(kbclient *kbver.Clientset) string {
+	if _, err := kbclient.Discovery().ServerResourcesForGroupVersion(v1alpha2.SchemeGroupVersion.String()); err != nil {
+		glog.V(3).Infof("Version <%s> of scheduling API is not available: %v",
+			v1alpha2.SchemeGroupVersion, err)
+		return v1alpha1.GroupVersion
+	}
+
+	return v1alpha2.GroupVersion
+}
+
 func (sc *SchedulerCache) Run(stopCh <-chan struct{}) {
 	go sc.pdbInformer.Informer().Run(stopCh)
 	go sc.podInformer.Informer().Run(stopCh)
 	go sc.nodeInformer.Informer().Run(stopCh)
-	go sc.podGroupInformer.Informer().Run(stopCh)
+	go sc.podGroupInformer.Run(stopCh)
 	go sc.pvInformer.Informer().Run(stopCh)
 	go sc.pvcInformer.Informer().Run(stopCh)
 	go sc.scInformer.Informer().Run(stopCh)
-	go sc.queueInformer.Informer().Run(stopCh)
+	go sc.queueInformer.Run(stopCh)
+	go sc.reservationInformer.Informer().Run(stopCh)
 	go sc.pcInformer.Informer().Run(stopCh)
 
+	// Bind tasks to hosts.
+	sc.startBindWorkers(stopCh)
+
+	// Evict tasks.
+	sc.startEvictWorkers(stopCh)
+
+	// Clean up expired assumed tasks.
+	go wait.Until(sc.cleanupAssumedTasks, assumedTasksCleanupPeriod, stopCh)
+
 	// Re-sync error tasks.
 	go wait.Until(sc.processResyncTask, 0, stopCh)
 
@@ -318,12 +428,13 @@ func (sc *SchedulerCache) WaitForCacheSync(stopCh <-chan struct{}) bool {
 	return cache.WaitForCacheSync(stopCh,
 		sc.pdbInformer.Informer().HasSynced,
 		sc.podInformer.Informer().HasSynced,
-		sc.podGroupInformer.Informer().HasSynced,
+		sc.podGroupInformer.HasSynced,
 		sc.nodeInformer.Informer().HasSynced,
 		sc.pvInformer.Informer().HasSynced,
 		sc.pvcInformer.Informer().HasSynced,
 		sc.scInformer.Informer().HasSynced,
-		sc.queueInformer.Informer().HasSynced,
+		sc.queueInformer.HasSynced,
+		sc.reservationInformer.Informer().HasSynced,
 		sc.pcInformer.Informer().HasSynced,
 	)
 }
@@ -344,68 +455,121 @@ func (sc *SchedulerCache) findJobAndTask(taskInfo *kbapi.TaskInfo) (*kbapi.JobIn
 	return job, task, nil
 }
 
+// Evict evicts the task; the task is released in cache at once, and the pod is evicted
+// by workers. If the eviction is refused, e.g. by PodDisruptionBudget, the task is
+// rolled back.
 func (sc *SchedulerCache) Evict(taskInfo *kbapi.TaskInfo, reason string) error {
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
 
+	task, status, err := sc.releaseTask(taskInfo)
+	if err != nil {
+		return err
+	}
+	sc.enqueueEvict(task, status, reason)
+
+	return nil
+}
+
+// releaseTask moves the task to Releasing, and returns the task in cache with its
+// status before releasing; it's called with the lock of cache.
+func (sc *SchedulerCache) releaseTask(taskInfo *kbapi.TaskInfo) (*kbapi.TaskInfo, kbapi.TaskStatus, error) {
 	job, task, err := sc.findJobAndTask(taskInfo)
 
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
 	node, found := sc.Nodes[task.NodeName]
 	if !found {
-		return fmt.Errorf("failed to bind Task %v to host %v, host does not exist",
+		return nil, 0, fmt.Errorf("failed to bind Task %v to host %v, host does not exist",
 			task.UID, task.NodeName)
 	}
 
+	status := task.Status
 	err = job.UpdateTaskStatus(task, kbapi.Releasing)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
 	// Add new task to node.
 	if err := node.UpdateTask(task); err != nil {
+		return nil, 0, err
+	}
+
+	return task, status, nil
+}
+
+// Bind binds task to the target host.
+func (sc *SchedulerCache) Bind(taskInfo *kbapi.TaskInfo, hostname string) error {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	assumed, err := sc.assume(taskInfo, hostname)
+	if err != nil {
 		return err
 	}
+	sc.enqueueBind(assumed.task, hostname, assumed)
 
-	p := task.Pod
+	return nil
+}
 
-	go func() {
-		err := sc.Evictor.Evict(p)
-		if err != nil {
-			sc.resyncTask(task)
-		}
-	}()
+// Assume holds the resources of task on the host without binding it
+func (sc *SchedulerCache) Assume(taskInfo *kbapi.TaskInfo, hostname string) error {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
 
-	if !shadowPodGroup(job.PodGroup) {
-		sc.Recorder.Eventf(job.PodGroup, v1.EventTypeNormal, "Evict", reason)
+	_, err := sc.assume(taskInfo, hostname)
+	return err
+}
+
+// BindAssumed binds the assumed task to the host it's assumed on
+func (sc *SchedulerCache) BindAssumed(taskInfo *kbapi.TaskInfo) error {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	assumed, found := sc.assumedTasks[taskInfo.UID]
+	if !found {
+		return fmt.Errorf("failed to bind Task <%v/%v>, the assumption is expired",
+			taskInfo.Namespace, taskInfo.Name)
 	}
+	sc.enqueueBind(assumed.task, assumed.hostname, assumed)
 
 	return nil
 }
 
-// Bind binds task to the target host.
-func (sc *SchedulerCache) Bind(taskInfo *kbapi.TaskInfo, hostname string) error {
+// ForgetAssumed moves the assumed task back to Pending
+func (sc *SchedulerCache) ForgetAssumed(taskInfo *kbapi.TaskInfo) {
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
 
+	assumed, found := sc.assumedTasks[taskInfo.UID]
+	if !found {
+		return
+	}
+	delete(sc.assumedTasks, taskInfo.UID)
+
+	sc.rollbackBind(assumed.task, assumed.hostname)
+}
+
+// assume moves the task to Binding on the host, and records the assumption; it's
+// called with the lock of cache.
+func (sc *SchedulerCache) assume(taskInfo *kbapi.TaskInfo, hostname string) (*assumedTask, error) {
 	job, task, err := sc.findJobAndTask(taskInfo)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	node, found := sc.Nodes[hostname]
 	if !found {
-		return fmt.Errorf("failed to bind Task %v to host %v, host does not exist",
+		return nil, fmt.Errorf("failed to bind Task %v to host %v, host does not exist",
 			task.UID, hostname)
 	}
 
 	err = job.UpdateTaskStatus(task, kbapi.Binding)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Set `.nodeName` to the hostname
@@ -413,20 +577,10 @@ func (sc *SchedulerCache) Bind(taskInfo *kbapi.TaskInfo, hostname string) error
 
 	// Add task to the node.
 	if err := node.AddTask(task); err != nil {
-		return err
+		return nil, err
 	}
 
-	p := task.Pod
-
-	go func() {
-		if err := sc.Binder.Bind(p, hostname); err != nil {
-			sc.resyncTask(task)
-		} else {
-			sc.Recorder.Eventf(p, v1.EventTypeNormal, "Scheduled", "Successfully assigned %v/%v to %v", p.Namespace, p.Name, hostname)
-		}
-	}()
-
-	return nil
+	return sc.assumeTask(task, hostname), nil
 }
 
 // AllocateVolume allocates volume on the host to the task
@@ -446,7 +600,7 @@ func (sc *SchedulerCache) taskUnschedulable(task *api.TaskInfo, message string)
 
 	pod := task.Pod.DeepCopy()
 
-	sc.Recorder.Eventf(pod, v1.EventTypeWarning, string(v1.PodReasonUnschedulable), message)
+	sc.Recorder.Event(pod, v1.EventTypeWarning, string(v1.PodReasonUnschedulable), message)
 	if _, err := sc.StatusUpdater.UpdatePodCondition(pod, &v1.PodCondition{
 		Type:    v1.PodScheduled,
 		Status:  v1.ConditionFalse,
@@ -515,19 +669,24 @@ func (sc *SchedulerCache) Snapshot() *kbapi.ClusterInfo {
 	defer sc.Mutex.Unlock()
 
 	snapshot := &kbapi.ClusterInfo{
-		Nodes:  make(map[string]*kbapi.NodeInfo),
+		Nodes:  sc.snapshotNodes(),
 		Jobs:   make(map[kbapi.JobID]*kbapi.JobInfo),
 		Queues: make(map[kbapi.QueueID]*kbapi.QueueInfo),
-	}
 
-	for _, value := range sc.Nodes {
-		snapshot.Nodes[value.Name] = value.Clone()
+		Reservations: make(map[kbapi.ReservationID]*kbapi.ReservationInfo),
+
+		DisruptionBudgets: sc.disruptionBudgets(),
 	}
 
 	for _, value := range sc.Queues {
 		snapshot.Queues[value.UID] = value.Clone()
 	}
 
+	for _, value := range sc.Reservations {
+		snapshot.Reservations[value.UID] = value.Clone()
+	}
+
+	jobs := make(map[kbapi.JobID]*jobSnapshot, len(sc.Jobs))
 	for _, value := range sc.Jobs {
 		// If no scheduling spec, does not handle it.
 		if value.PodGroup == nil && value.PDB == nil {
@@ -555,8 +714,9 @@ func (sc *SchedulerCache) Snapshot() *kbapi.ClusterInfo {
 				value.Namespace, value.Name, priName, value.Priority)
 		}
 
-		snapshot.Jobs[value.UID] = value.Clone()
+		snapshot.Jobs[value.UID] = sc.snapshotJob(value, jobs)
 	}
+	sc.snapshots.jobs = jobs
 
 	glog.V(3).Infof("There are <%d> Jobs, <%d> Queues and <%d> Nodes in total for scheduling.",
 		len(snapshot.Jobs), len(snapshot.Queues), len(snapshot.Nodes))
@@ -608,7 +768,7 @@ func (sc *SchedulerCache) RecordJobStatusEvent(job *kbapi.JobInfo) {
 		if pgUnschedulable || pdbUnschedulabe {
 			msg := fmt.Sprintf("%v/%v tasks in gang unschedulable: %v",
 				len(job.TaskStatusIndex[api.Pending]), len(job.Tasks), job.FitError())
-			sc.Recorder.Eventf(job.PodGroup, v1.EventTypeWarning,
+			sc.Recorder.Event(job.PodGroup, v1.EventTypeWarning,
 				string(v1alpha1.PodGroupUnschedulableType), msg)
 		}
 	}
@@ -638,3 +798,12 @@ func (sc *SchedulerCache) UpdateJobStatus(job *kbapi.JobInfo) (*kbapi.JobInfo, e
 
 	return job, nil
 }
+
+// UpdateQueueStatus updates the status of queue.
+func (sc *SchedulerCache) UpdateQueueStatus(queue *kbapi.QueueInfo) error {
+	if _, err := sc.StatusUpdater.UpdateQueueStatus(queue.Queue); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -20,11 +20,18 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 )
@@ -205,6 +212,7 @@ func TestAddNode(t *testing.T) {
 
 	ni1 := api.NewNodeInfo(node1)
 	ni1.AddTask(pi2)
+	ni1.Generation = 2 // The task is added before the node.
 
 	j1 := api.NewJobInfo("j1")
 	pg1 := createShadowPodGroup(pod1)
@@ -307,3 +315,253 @@ func TestGetOrCreateJob(t *testing.T) {
 		}
 	}
 }
+
+type failedBinder struct{}
+
+func (fb *failedBinder) Bind(p *v1.Pod, hostname string) error {
+	return fmt.Errorf("failed to bind pod <%v/%v>", p.Namespace, p.Name)
+}
+
+func TestBindFailure(t *testing.T) {
+	owner := buildOwnerReference("j1")
+	pod := buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{owner}, make(map[string]string))
+	node := buildNode("n1", buildResourceList("2000m", "10G"))
+
+	cache := &SchedulerCache{
+		Nodes:    make(map[string]*api.NodeInfo),
+		Jobs:     make(map[api.JobID]*api.JobInfo),
+		Binder:   &failedBinder{},
+		Recorder: record.NewFakeRecorder(10),
+		errTasks: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	cache.AddNode(node)
+	cache.AddPod(pod)
+
+	task := api.NewTaskInfo(pod)
+	task.Job = cache.getOrCreateJob(task).UID
+
+	if err := cache.Bind(task, "n1"); err != nil {
+		t.Fatalf("failed to bind task: %v", err)
+	}
+
+	err := wait.Poll(10*time.Millisecond, 3*time.Second, func() (bool, error) {
+		cache.Mutex.Lock()
+		defer cache.Mutex.Unlock()
+
+		_, found := cache.Nodes["n1"].Tasks[api.PodKey(pod)]
+		return !found && cache.Jobs[task.Job].Tasks[task.UID].Status == api.Pending, nil
+	})
+	if err != nil {
+		t.Fatalf("expected task to be rolled back after bind failure: %v", err)
+	}
+
+	if idle := cache.Nodes["n1"].Idle; !reflect.DeepEqual(idle, buildResource("2000m", "10G")) {
+		t.Errorf("expected idle resources of node to be released, got %v", idle)
+	}
+
+	if cache.errTasks.Len() != 1 {
+		t.Errorf("expected task to be re-synced, got %d tasks", cache.errTasks.Len())
+	}
+}
+
+type refusedEvictor struct{}
+
+func (re *refusedEvictor) Evict(p *v1.Pod, reason string) error {
+	return &EvictionRefusedError{Pod: p, Err: fmt.Errorf("cannot evict pod as it would violate the pod's disruption budget")}
+}
+
+func TestEvictRefused(t *testing.T) {
+	owner := buildOwnerReference("j1")
+	pod := buildPod("c1", "p1", "n1", v1.PodRunning, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{owner}, make(map[string]string))
+	pod.Labels = map[string]string{"app": "a1"}
+	node := buildNode("n1", buildResourceList("2000m", "10G"))
+
+	pdbInformer := informers.NewSharedInformerFactory(nil, 0).Policy().V1beta1().PodDisruptionBudgets()
+	pdbInformer.Informer().GetIndexer().Add(&policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pdb1",
+			Namespace:       "c1",
+			ResourceVersion: "1",
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "a1"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			PodDisruptionsAllowed: 1,
+		},
+	})
+
+	cache := &SchedulerCache{
+		Nodes:        make(map[string]*api.NodeInfo),
+		Jobs:         make(map[api.JobID]*api.JobInfo),
+		Evictor:      &refusedEvictor{},
+		EvictOptions: EvictOptions{Mode: EvictionModeEviction},
+		Recorder:     record.NewFakeRecorder(10),
+		errTasks:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		pdbInformer:  pdbInformer,
+	}
+	cache.AddNode(node)
+	cache.AddPod(pod)
+
+	// Process the requests by hand instead of workers.
+	cache.evictWorkers.once.Do(func() {})
+	cache.evictWorkers.requests = workqueue.New()
+
+	task := api.NewTaskInfo(pod)
+	task.Job = cache.getOrCreateJob(task).UID
+
+	if err := cache.Evict(task, "preempted by Task <c1/p2>"); err != nil {
+		t.Fatalf("failed to evict task: %v", err)
+	}
+
+	if status := cache.Jobs[task.Job].Tasks[task.UID].Status; status != api.Releasing {
+		t.Errorf("expected task to be %v before eviction, got %v", api.Releasing, status)
+	}
+
+	cache.evictWorkers.requests.ShutDown()
+	cache.processEvictRequest()
+
+	if status := cache.Jobs[task.Job].Tasks[task.UID].Status; status != api.Running {
+		t.Errorf("expected task to be rolled back to %v, got %v", api.Running, status)
+	}
+
+	if releasing := cache.Nodes["n1"].Releasing; !reflect.DeepEqual(releasing, api.EmptyResource()) {
+		t.Errorf("expected no releasing resources on node, got %v", releasing)
+	}
+
+	// The budget refusing the eviction is exhausted until it's updated.
+	if budgets := cache.disruptionBudgets(); len(budgets) != 1 || budgets[0].Allowed != 0 {
+		t.Errorf("expected budget refusing eviction to be exhausted, got %v", budgets)
+	}
+
+	pdbInformer.Informer().GetIndexer().Update(&policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pdb1",
+			Namespace:       "c1",
+			ResourceVersion: "2",
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "a1"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			PodDisruptionsAllowed: 1,
+		},
+	})
+	if budgets := cache.disruptionBudgets(); len(budgets) != 1 || budgets[0].Allowed != 1 {
+		t.Errorf("expected updated budget to be honored, got %v", budgets)
+	}
+}
+
+type fakeBinder struct{}
+
+func (fb *fakeBinder) Bind(p *v1.Pod, hostname string) error {
+	return nil
+}
+
+func TestExpireAssumedTasks(t *testing.T) {
+	owner := buildOwnerReference("j1")
+	pod := buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{owner}, make(map[string]string))
+	node := buildNode("n1", buildResourceList("2000m", "10G"))
+
+	cache := &SchedulerCache{
+		Nodes:    make(map[string]*api.NodeInfo),
+		Jobs:     make(map[api.JobID]*api.JobInfo),
+		Binder:   &fakeBinder{},
+		Recorder: record.NewFakeRecorder(10),
+		errTasks: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	cache.AddNode(node)
+	cache.AddPod(pod)
+
+	task := api.NewTaskInfo(pod)
+	task.Job = cache.getOrCreateJob(task).UID
+
+	if err := cache.Bind(task, "n1"); err != nil {
+		t.Fatalf("failed to bind task: %v", err)
+	}
+
+	cache.Mutex.Lock()
+	defer cache.Mutex.Unlock()
+
+	// The binding is not confirmed, but not expired yet.
+	cache.expireAssumedTasks(time.Now())
+	if status := cache.Jobs[task.Job].Tasks[task.UID].Status; status != api.Binding {
+		t.Errorf("expected task to be in %v before expiration, got %v", api.Binding, status)
+	}
+
+	cache.expireAssumedTasks(time.Now().Add(DefaultAssumedTTL))
+	if status := cache.Jobs[task.Job].Tasks[task.UID].Status; status != api.Pending {
+		t.Errorf("expected task to be in %v after expiration, got %v", api.Pending, status)
+	}
+
+	if idle := cache.Nodes["n1"].Idle; !reflect.DeepEqual(idle, buildResource("2000m", "10G")) {
+		t.Errorf("expected idle resources of node to be released, got %v", idle)
+	}
+
+	if len(cache.assumedTasks) != 0 {
+		t.Errorf("expected no assumed tasks after expiration, got %d", len(cache.assumedTasks))
+	}
+}
+
+type recordedBinder struct {
+	binds []string
+}
+
+func (rb *recordedBinder) Bind(p *v1.Pod, hostname string) error {
+	rb.binds = append(rb.binds, fmt.Sprintf("%v/%v", p.Name, hostname))
+	return nil
+}
+
+func TestDropStaleBindRequests(t *testing.T) {
+	owner := buildOwnerReference("j1")
+	pod := buildPod("c1", "p1", "", v1.PodPending, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{owner}, make(map[string]string))
+
+	binder := &recordedBinder{}
+	cache := &SchedulerCache{
+		Nodes:    make(map[string]*api.NodeInfo),
+		Jobs:     make(map[api.JobID]*api.JobInfo),
+		Binder:   binder,
+		Recorder: record.NewFakeRecorder(10),
+		errTasks: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	cache.AddNode(buildNode("n1", buildResourceList("2000m", "10G")))
+	cache.AddNode(buildNode("n2", buildResourceList("2000m", "10G")))
+	cache.AddPod(pod)
+
+	// The requests are processed by hand instead of workers.
+	cache.bindWorkers.once.Do(func() {})
+	cache.bindWorkers.requests = workqueue.New()
+	cache.bindWorkers.results = make(chan *bindResult, 10)
+	cache.bindWorkers.limiter = flowcontrol.NewFakeAlwaysRateLimiter()
+
+	task := api.NewTaskInfo(pod)
+	task.Job = cache.getOrCreateJob(task).UID
+
+	if err := cache.Bind(task, "n1"); err != nil {
+		t.Fatalf("failed to bind task: %v", err)
+	}
+
+	// The binding to n1 is expired before the request is processed, and the
+	// task is bound to n2 in the next session.
+	cache.Mutex.Lock()
+	cache.expireAssumedTasks(time.Now().Add(DefaultAssumedTTL))
+	cache.Mutex.Unlock()
+
+	if err := cache.Bind(task, "n2"); err != nil {
+		t.Fatalf("failed to bind task: %v", err)
+	}
+
+	cache.bindWorkers.requests.ShutDown()
+	cache.processBindRequest()
+
+	if expected := []string{"p1/n2"}; !reflect.DeepEqual(binder.binds, expected) {
+		t.Errorf("expected binds %v, got %v", expected, binder.binds)
+	}
+	if len(cache.bindWorkers.results) != 1 {
+		t.Errorf("expected 1 bind result, got %d", len(cache.bindWorkers.results))
+	}
+}
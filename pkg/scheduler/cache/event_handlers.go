@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	kbv1alpha2 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha2"
 	"github.com/kubernetes-sigs/kube-batch/pkg/apis/utils"
 	kbapi "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 )
@@ -38,6 +39,14 @@ func isTerminated(status kbapi.TaskStatus) bool {
 	return status == kbapi.Succeeded || status == kbapi.Failed
 }
 
+// newTaskInfo creates TaskInfo with the resources calculated by PodRequest of cache.
+func (sc *SchedulerCache) newTaskInfo(pod *v1.Pod) *kbapi.TaskInfo {
+	if sc.PodRequest == nil {
+		return kbapi.NewTaskInfo(pod)
+	}
+	return kbapi.NewTaskInfoWithRequest(pod, sc.PodRequest)
+}
+
 // getOrCreateJob will return corresponding Job for pi if it exists, or it will create a Job and return it if
 // pi.Pod.Spec.SchedulerName is same as kube-batch scheduler's name, otherwise it will return nil.
 func (sc *SchedulerCache) getOrCreateJob(pi *kbapi.TaskInfo) *kbapi.JobInfo {
@@ -89,7 +98,7 @@ func (sc *SchedulerCache) addTask(pi *kbapi.TaskInfo) error {
 
 // Assumes that lock is already acquired.
 func (sc *SchedulerCache) addPod(pod *v1.Pod) error {
-	pi := kbapi.NewTaskInfo(pod)
+	pi := sc.newTaskInfo(pod)
 
 	return sc.addTask(pi)
 }
@@ -109,7 +118,7 @@ func (sc *SchedulerCache) syncTask(oldTask *kbapi.TaskInfo) error {
 		return fmt.Errorf("failed to get Pod <%v/%v>: err %v", oldTask.Namespace, oldTask.Name, err)
 	}
 
-	newTask := kbapi.NewTaskInfo(newPod)
+	newTask := sc.newTaskInfo(newPod)
 
 	return sc.updateTask(oldTask, newTask)
 }
@@ -158,7 +167,7 @@ func (sc *SchedulerCache) deleteTask(pi *kbapi.TaskInfo) error {
 
 // Assumes that lock is already acquired.
 func (sc *SchedulerCache) deletePod(pod *v1.Pod) error {
-	pi := kbapi.NewTaskInfo(pod)
+	pi := sc.newTaskInfo(pod)
 
 	// Delete the Task in cache to handle Binding status.
 	task := pi
@@ -412,7 +421,7 @@ func (sc *SchedulerCache) deletePodGroup(ss *kbv1.PodGroup) error {
 }
 
 func (sc *SchedulerCache) AddPodGroup(obj interface{}) {
-	ss, ok := obj.(*kbv1.PodGroup)
+	ss, ok := toPodGroup(obj)
 	if !ok {
 		glog.Errorf("Cannot convert to *kbv1.PodGroup: %v", obj)
 		return
@@ -431,12 +440,12 @@ func (sc *SchedulerCache) AddPodGroup(obj interface{}) {
 }
 
 func (sc *SchedulerCache) UpdatePodGroup(oldObj, newObj interface{}) {
-	oldSS, ok := oldObj.(*kbv1.PodGroup)
+	oldSS, ok := toPodGroup(oldObj)
 	if !ok {
 		glog.Errorf("Cannot convert oldObj to *kbv1.SchedulingSpec: %v", oldObj)
 		return
 	}
-	newSS, ok := newObj.(*kbv1.PodGroup)
+	newSS, ok := toPodGroup(newObj)
 	if !ok {
 		glog.Errorf("Cannot convert newObj to *kbv1.SchedulingSpec: %v", newObj)
 		return
@@ -456,11 +465,11 @@ func (sc *SchedulerCache) UpdatePodGroup(oldObj, newObj interface{}) {
 func (sc *SchedulerCache) DeletePodGroup(obj interface{}) {
 	var ss *kbv1.PodGroup
 	switch t := obj.(type) {
-	case *kbv1.PodGroup:
-		ss = t
+	case *kbv1.PodGroup, *kbv1alpha2.PodGroup:
+		ss, _ = toPodGroup(t)
 	case cache.DeletedFinalStateUnknown:
 		var ok bool
-		ss, ok = t.Obj.(*kbv1.PodGroup)
+		ss, ok = toPodGroup(t.Obj)
 		if !ok {
 			glog.Errorf("Cannot convert to *kbv1.SchedulingSpec: %v", t.Obj)
 			return
@@ -592,7 +601,7 @@ func (sc *SchedulerCache) DeletePDB(obj interface{}) {
 }
 
 func (sc *SchedulerCache) AddQueue(obj interface{}) {
-	ss, ok := obj.(*kbv1.Queue)
+	ss, ok := toQueue(obj)
 	if !ok {
 		glog.Errorf("Cannot convert to *kbv1.Queue: %v", obj)
 		return
@@ -611,12 +620,12 @@ func (sc *SchedulerCache) AddQueue(obj interface{}) {
 }
 
 func (sc *SchedulerCache) UpdateQueue(oldObj, newObj interface{}) {
-	oldSS, ok := oldObj.(*kbv1.Queue)
+	oldSS, ok := toQueue(oldObj)
 	if !ok {
 		glog.Errorf("Cannot convert oldObj to *kbv1.Queue: %v", oldObj)
 		return
 	}
-	newSS, ok := newObj.(*kbv1.Queue)
+	newSS, ok := toQueue(newObj)
 	if !ok {
 		glog.Errorf("Cannot convert newObj to *kbv1.Queue: %v", newObj)
 		return
@@ -636,11 +645,11 @@ func (sc *SchedulerCache) UpdateQueue(oldObj, newObj interface{}) {
 func (sc *SchedulerCache) DeleteQueue(obj interface{}) {
 	var ss *kbv1.Queue
 	switch t := obj.(type) {
-	case *kbv1.Queue:
-		ss = t
+	case *kbv1.Queue, *kbv1alpha2.Queue:
+		ss, _ = toQueue(t)
 	case cache.DeletedFinalStateUnknown:
 		var ok bool
-		ss, ok = t.Obj.(*kbv1.Queue)
+		ss, ok = toQueue(t.Obj)
 		if !ok {
 			glog.Errorf("Cannot convert to *kbv1.Queue: %v", t.Obj)
 			return
@@ -775,3 +784,66 @@ func (sc *SchedulerCache) addPriorityClass(pc *v1beta1.PriorityClass) {
 
 	sc.PriorityClasses[pc.Name] = pc
 }
+
+// AddReservation add reservation to scheduler cache
+func (sc *SchedulerCache) AddReservation(obj interface{}) {
+	r, ok := obj.(*kbv1.Reservation)
+	if !ok {
+		glog.Errorf("Cannot convert to *kbv1.Reservation: %v", obj)
+		return
+	}
+
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	glog.V(4).Infof("Add Reservation(%s/%s) into cache, spec(%#v)", r.Namespace, r.Name, r.Spec)
+	sc.setReservation(r)
+}
+
+// UpdateReservation update reservation to scheduler cache
+func (sc *SchedulerCache) UpdateReservation(oldObj, newObj interface{}) {
+	r, ok := newObj.(*kbv1.Reservation)
+	if !ok {
+		glog.Errorf("Cannot convert newObj to *kbv1.Reservation: %v", newObj)
+		return
+	}
+
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	sc.setReservation(r)
+}
+
+// DeleteReservation delete reservation from scheduler cache
+func (sc *SchedulerCache) DeleteReservation(obj interface{}) {
+	var r *kbv1.Reservation
+	switch t := obj.(type) {
+	case *kbv1.Reservation:
+		r = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		r, ok = t.Obj.(*kbv1.Reservation)
+		if !ok {
+			glog.Errorf("Cannot convert to *kbv1.Reservation: %v", t.Obj)
+			return
+		}
+	default:
+		glog.Errorf("Cannot convert to *kbv1.Reservation: %v", t)
+		return
+	}
+
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	sc.deleteReservation(r)
+}
+
+func (sc *SchedulerCache) setReservation(r *kbv1.Reservation) {
+	ri := kbapi.NewReservationInfo(r)
+	sc.Reservations[ri.UID] = ri
+}
+
+func (sc *SchedulerCache) deleteReservation(r *kbv1.Reservation) {
+	ri := kbapi.NewReservationInfo(r)
+	delete(sc.Reservations, ri.UID)
+}
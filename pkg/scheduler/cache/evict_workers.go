@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	kbapi "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+// DefaultEvictParallelism is the default number of workers evicting tasks.
+const DefaultEvictParallelism = 16
+
+// evictRequest is a request to evict the pod of task.
+type evictRequest struct {
+	task *kbapi.TaskInfo
+	pod  *v1.Pod
+	// status is the status of task before eviction, which the task is rolled back to
+	// if the eviction is refused.
+	status kbapi.TaskStatus
+	reason string
+}
+
+// evictWorkers evicts tasks out of the session, so the API calls of eviction do not
+// block scheduling.
+type evictWorkers struct {
+	once sync.Once
+
+	requests workqueue.Interface
+}
+
+// startEvictWorkers starts the workers of cache; it only takes effect once.
+func (sc *SchedulerCache) startEvictWorkers(stopCh <-chan struct{}) {
+	sc.evictWorkers.once.Do(func() {
+		sc.evictWorkers.requests = workqueue.New()
+
+		glog.V(3).Infof("Starting %d evict workers", DefaultEvictParallelism)
+
+		for i := 0; i < DefaultEvictParallelism; i++ {
+			go wait.Until(sc.processEvictRequest, 0, stopCh)
+		}
+
+		go func() {
+			<-stopCh
+			sc.evictWorkers.requests.ShutDown()
+		}()
+	})
+}
+
+// enqueueEvict adds an evict request; it never blocks, so it's safe to call with the lock of cache.
+func (sc *SchedulerCache) enqueueEvict(task *kbapi.TaskInfo, status kbapi.TaskStatus, reason string) {
+	sc.startEvictWorkers(wait.NeverStop)
+
+	sc.evictWorkers.requests.Add(&evictRequest{
+		task:   task,
+		pod:    task.Pod,
+		status: status,
+		reason: reason,
+	})
+}
+
+func (sc *SchedulerCache) processEvictRequest() {
+	for {
+		obj, shutdown := sc.evictWorkers.requests.Get()
+		if shutdown {
+			return
+		}
+
+		req := obj.(*evictRequest)
+		err := sc.Evictor.Evict(req.pod, req.reason)
+		sc.evictWorkers.requests.Done(obj)
+
+		sc.processEvictResult(req, err)
+	}
+}
+
+// processEvictResult records the eviction, or rolls back the task if the eviction
+// failed; the budgets refusing the eviction are taken as exhausted by the following
+// sessions, until they're updated.
+func (sc *SchedulerCache) processEvictResult(req *evictRequest, err error) {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	if err == nil {
+		if job, found := sc.Jobs[req.task.Job]; found && !shadowPodGroup(job.PodGroup) {
+			sc.Recorder.Event(job.PodGroup, v1.EventTypeNormal, "Evict", req.reason)
+		}
+		return
+	}
+
+	glog.Errorf("Failed to evict Task <%v/%v>: %v", req.pod.Namespace, req.pod.Name, err)
+
+	if _, refused := err.(*EvictionRefusedError); refused {
+		sc.refuseDisruption(req.task)
+	}
+
+	sc.rollbackEvict(req.task, req.status)
+	sc.resyncTask(req.task)
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+
+	kbapi "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+const (
+	// EvictionModeDelete evicts pods by deleting them, which bypasses PodDisruptionBudgets.
+	EvictionModeDelete = "delete"
+	// EvictionModeEviction evicts pods by the Eviction subresource, which honors
+	// PodDisruptionBudgets.
+	EvictionModeEviction = "eviction"
+
+	// DisruptionTarget is the condition set on pods which are going to be evicted.
+	DisruptionTarget v1.PodConditionType = "DisruptionTarget"
+	// PreemptionByKubeBatchReason is the reason of DisruptionTarget condition
+	// for the pods evicted by kube-batch.
+	PreemptionByKubeBatchReason = "PreemptionByKubeBatch"
+	// EvictionRefusedReason is the reason of DisruptionTarget condition if the
+	// eviction was refused, e.g. by PodDisruptionBudget.
+	EvictionRefusedReason = "EvictionRefused"
+)
+
+// EvictionRefusedError is returned by Evictor if the eviction is refused by
+// PodDisruptionBudget.
+type EvictionRefusedError struct {
+	Pod *v1.Pod
+	Err error
+}
+
+func (e *EvictionRefusedError) Error() string {
+	return fmt.Sprintf("eviction of pod <%v/%v> refused by PodDisruptionBudget: %v",
+		e.Pod.Namespace, e.Pod.Name, e.Err)
+}
+
+// EvictOptions configures how the pods are evicted.
+type EvictOptions struct {
+	// Mode is either EvictionModeDelete or EvictionModeEviction.
+	Mode string
+	// GracePeriodSeconds is the grace period of evicted pods; the grace period
+	// of pod is used if it's negative.
+	GracePeriodSeconds int64
+	// PriorityClassGracePeriodSeconds overrides GracePeriodSeconds for the pods
+	// of the PriorityClasses.
+	PriorityClassGracePeriodSeconds map[string]int64
+}
+
+// DefaultEvictOptions returns the EvictOptions which deletes pods with their own grace period.
+func DefaultEvictOptions() EvictOptions {
+	return EvictOptions{
+		Mode:               EvictionModeDelete,
+		GracePeriodSeconds: -1,
+	}
+}
+
+// honorDisruptionBudgets returns whether the evictions honor PodDisruptionBudgets.
+func (eo EvictOptions) honorDisruptionBudgets() bool {
+	return eo.Mode == EvictionModeEviction
+}
+
+// deleteOptions returns the DeleteOptions used to evict the pod.
+func (eo EvictOptions) deleteOptions(p *v1.Pod) *metav1.DeleteOptions {
+	opts := &metav1.DeleteOptions{
+		Preconditions: metav1.NewUIDPreconditions(string(p.UID)),
+	}
+
+	gracePeriod := eo.GracePeriodSeconds
+	if gp, found := eo.PriorityClassGracePeriodSeconds[p.Spec.PriorityClassName]; found {
+		gracePeriod = gp
+	}
+	if gracePeriod >= 0 {
+		opts.GracePeriodSeconds = &gracePeriod
+	}
+
+	return opts
+}
+
+func newEvictor(kubeclient *kubernetes.Clientset, opts EvictOptions) Evictor {
+	if opts.Mode == EvictionModeEviction {
+		return &evictionEvictor{kubeclient: kubeclient, opts: opts}
+	}
+
+	return &defaultEvictor{kubeclient: kubeclient, opts: opts}
+}
+
+// evictionEvictor evicts pods by the Eviction subresource, so the PodDisruptionBudgets
+// are honored by API server.
+type evictionEvictor struct {
+	kubeclient *kubernetes.Clientset
+	opts       EvictOptions
+}
+
+func (ee *evictionEvictor) Evict(p *v1.Pod, reason string) error {
+	glog.V(3).Infof("Evicting pod %v/%v by Eviction", p.Namespace, p.Name)
+
+	markDisruptionTarget(ee.kubeclient, p, v1.ConditionTrue, PreemptionByKubeBatchReason, reason)
+
+	err := ee.kubeclient.PolicyV1beta1().Evictions(p.Namespace).Evict(&policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Namespace: p.Namespace, Name: p.Name},
+		DeleteOptions: ee.opts.deleteOptions(p),
+	})
+	if err == nil || errors.IsNotFound(err) {
+		return nil
+	}
+
+	if errors.IsTooManyRequests(err) {
+		err = &EvictionRefusedError{Pod: p, Err: err}
+	}
+	glog.Errorf("Failed to evict pod <%v/%v>: %v", p.Namespace, p.Name, err)
+
+	markDisruptionTarget(ee.kubeclient, p, v1.ConditionFalse, EvictionRefusedReason, err.Error())
+
+	return err
+}
+
+// markDisruptionTarget updates the DisruptionTarget condition of pod; it's best effort,
+// so the eviction goes on if failed.
+func markDisruptionTarget(kubeclient *kubernetes.Clientset, p *v1.Pod,
+	status v1.ConditionStatus, reason, message string) {
+	pod := p.DeepCopy()
+	condition := &v1.PodCondition{
+		Type:    DisruptionTarget,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+	if !podutil.UpdatePodCondition(&pod.Status, condition) {
+		return
+	}
+
+	if _, err := kubeclient.CoreV1().Pods(pod.Namespace).UpdateStatus(pod); err != nil {
+		glog.Warningf("Failed to update condition %s of pod <%v/%v>: %v",
+			DisruptionTarget, pod.Namespace, pod.Name, err)
+	}
+}
+
+// disruptionBudgets returns the PodDisruptionBudgets honored by evictions.
+func (sc *SchedulerCache) disruptionBudgets() []*kbapi.DisruptionBudgetInfo {
+	if !sc.EvictOptions.honorDisruptionBudgets() || sc.pdbInformer == nil {
+		return nil
+	}
+
+	pdbs, err := sc.pdbInformer.Lister().List(labels.Everything())
+	if err != nil {
+		glog.Errorf("Failed to list PodDisruptionBudgets: %v", err)
+		return nil
+	}
+
+	var budgets []*kbapi.DisruptionBudgetInfo
+	for _, pdb := range pdbs {
+		budget, err := kbapi.NewDisruptionBudgetInfo(pdb)
+		if err != nil {
+			glog.Errorf("Failed to build PodDisruptionBudget <%v/%v>: %v",
+				pdb.Namespace, pdb.Name, err)
+			continue
+		}
+
+		key := pdb.Namespace + "/" + pdb.Name
+		if version, found := sc.refusedBudgets[key]; found {
+			if version == pdb.ResourceVersion {
+				budget.Allowed = 0
+			} else {
+				delete(sc.refusedBudgets, key)
+			}
+		}
+
+		budgets = append(budgets, budget)
+	}
+
+	return budgets
+}
+
+// refuseDisruption records the PodDisruptionBudgets covering the task, which refused
+// its eviction; they're taken as exhausted until they're updated, as the disruptions
+// allowed in their status are stale.
+func (sc *SchedulerCache) refuseDisruption(task *kbapi.TaskInfo) {
+	if sc.pdbInformer == nil {
+		return
+	}
+
+	pdbs, err := sc.pdbInformer.Lister().PodDisruptionBudgets(task.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.Errorf("Failed to list PodDisruptionBudgets in <%v>: %v", task.Namespace, err)
+		return
+	}
+
+	for _, pdb := range pdbs {
+		budget, err := kbapi.NewDisruptionBudgetInfo(pdb)
+		if err != nil || !budget.Covers(task) {
+			continue
+		}
+
+		if sc.refusedBudgets == nil {
+			sc.refusedBudgets = map[string]string{}
+		}
+		sc.refusedBudgets[pdb.Namespace+"/"+pdb.Name] = pdb.ResourceVersion
+	}
+}
+
+// rollbackEvict moves the task back to its status before eviction, if the task
+// is still releasing.
+func (sc *SchedulerCache) rollbackEvict(taskInfo *kbapi.TaskInfo, status kbapi.TaskStatus) {
+	job, task, err := sc.findJobAndTask(taskInfo)
+	if err != nil {
+		glog.V(3).Infof("Ignore rollback of Task <%v/%v>: %v", taskInfo.Namespace, taskInfo.Name, err)
+		return
+	}
+
+	if task.Status != kbapi.Releasing {
+		return
+	}
+
+	if err := job.UpdateTaskStatus(task, status); err != nil {
+		glog.Errorf("Failed to update status of Task <%v/%v> to %v: %v",
+			task.Namespace, task.Name, status, err)
+	}
+
+	if node, found := sc.Nodes[task.NodeName]; found {
+		if err := node.UpdateTask(task); err != nil {
+			glog.Errorf("Failed to update Task <%v/%v> on <%v>: %v",
+				task.Namespace, task.Name, task.NodeName, err)
+		}
+	}
+}
@@ -35,11 +35,24 @@ type Cache interface {
 	// WaitForCacheSync waits for all cache synced
 	WaitForCacheSync(stopCh <-chan struct{}) bool
 
-	// Bind binds Task to the target host.
-	// TODO(jinzhej): clean up expire Tasks.
+	// Bind binds Task to the target host; if the binding is not confirmed in
+	// time, the Task is moved back to Pending.
 	Bind(task *api.TaskInfo, hostname string) error
 
-	// Evict evicts the task to release resources.
+	// Assume holds the resources of Task on the target host before it's bound;
+	// the Task is bound by BindAssumed, or moved back to Pending by ForgetAssumed.
+	// Like Bind, the Task is moved back to Pending if it's not bound in time.
+	Assume(task *api.TaskInfo, hostname string) error
+
+	// BindAssumed binds the assumed Task to its host.
+	BindAssumed(task *api.TaskInfo) error
+
+	// ForgetAssumed moves the assumed Task back to Pending, and releases its
+	// resources on host.
+	ForgetAssumed(task *api.TaskInfo)
+
+	// Evict evicts the task to release resources; the pod is evicted asynchronously,
+	// and the task is rolled back if the eviction is refused.
 	Evict(task *api.TaskInfo, reason string) error
 
 	// RecordJobStatusEvent records related events according to job status.
@@ -49,6 +62,9 @@ type Cache interface {
 	// UpdateJobStatus puts job in backlog for a while.
 	UpdateJobStatus(job *api.JobInfo) (*api.JobInfo, error)
 
+	// UpdateQueueStatus updates the status of queue.
+	UpdateQueueStatus(queue *api.QueueInfo) error
+
 	// AllocateVolumes allocates volume on the host to the task
 	AllocateVolumes(task *api.TaskInfo, hostname string) error
 
@@ -66,11 +82,12 @@ type Binder interface {
 }
 
 type Evictor interface {
-	Evict(pod *v1.Pod) error
+	Evict(pod *v1.Pod, reason string) error
 }
 
 // StatusUpdater updates pod with given PodCondition
 type StatusUpdater interface {
 	UpdatePodCondition(pod *v1.Pod, podCondition *v1.PodCondition) (*v1.Pod, error)
 	UpdatePodGroup(pg *v1alpha1.PodGroup) (*v1alpha1.PodGroup, error)
+	UpdateQueueStatus(queue *v1alpha1.Queue) (*v1alpha1.Queue, error)
 }
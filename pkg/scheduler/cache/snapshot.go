@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	kbapi "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+// snapshots keeps the clones of nodes and jobs handed to the last session; a clone
+// is shared by the next snapshot if neither the clone nor the object in cache is
+// changed since it's cloned, which is told by their generations.
+type snapshots struct {
+	nodes map[string]*nodeSnapshot
+	jobs  map[kbapi.JobID]*jobSnapshot
+}
+
+// nodeSnapshot is the clone of node in cache, with the generation of node when it's cloned.
+type nodeSnapshot struct {
+	source     *kbapi.NodeInfo
+	generation int64
+	clone      *kbapi.NodeInfo
+}
+
+func newNodeSnapshot(node *kbapi.NodeInfo) *nodeSnapshot {
+	return &nodeSnapshot{
+		source:     node,
+		generation: node.Generation,
+		clone:      node.Clone(),
+	}
+}
+
+// unchanged returns whether neither the node nor its clone is changed since cloned.
+func (ns *nodeSnapshot) unchanged(node *kbapi.NodeInfo) bool {
+	return ns != nil && ns.source == node &&
+		ns.generation == node.Generation && ns.clone.Generation == node.Generation
+}
+
+// jobSnapshot is the clone of job in cache, with the generation of job when it's cloned.
+type jobSnapshot struct {
+	source     *kbapi.JobInfo
+	generation int64
+	clone      *kbapi.JobInfo
+}
+
+func newJobSnapshot(job *kbapi.JobInfo) *jobSnapshot {
+	return &jobSnapshot{
+		source:     job,
+		generation: job.Generation,
+		clone:      job.Clone(),
+	}
+}
+
+// unchanged returns whether neither the job nor its clone is changed since cloned.
+func (js *jobSnapshot) unchanged(job *kbapi.JobInfo) bool {
+	return js != nil && js.source == job &&
+		js.generation == job.Generation && js.clone.Generation == job.Generation
+}
+
+// snapshotNodes returns the clones of nodes, and keeps them for next snapshot.
+func (sc *SchedulerCache) snapshotNodes() map[string]*kbapi.NodeInfo {
+	nodes := make(map[string]*kbapi.NodeInfo, len(sc.Nodes))
+	snapshots := make(map[string]*nodeSnapshot, len(sc.Nodes))
+
+	for name, node := range sc.Nodes {
+		ns := sc.snapshots.nodes[name]
+		if !ns.unchanged(node) {
+			ns = newNodeSnapshot(node)
+		}
+
+		snapshots[name] = ns
+		nodes[node.Name] = ns.clone
+	}
+	sc.snapshots.nodes = snapshots
+
+	return nodes
+}
+
+// snapshotJob returns the clone of job, and keeps it for next snapshot; the fields
+// which are not tracked by generation, e.g. Priority, are refreshed on shared clone.
+func (sc *SchedulerCache) snapshotJob(job *kbapi.JobInfo, snapshots map[kbapi.JobID]*jobSnapshot) *kbapi.JobInfo {
+	js := sc.snapshots.jobs[job.UID]
+	if !js.unchanged(job) {
+		js = newJobSnapshot(job)
+	} else {
+		js.clone.Priority = job.Priority
+		js.clone.PodGroup = job.PodGroup
+		js.clone.Queue = job.Queue
+		// NodesFitDelta is only valid in the session which calculated it.
+		if len(js.clone.NodesFitDelta) != 0 {
+			js.clone.NodesFitDelta = make(kbapi.NodeResourceMap)
+		}
+	}
+	snapshots[job.UID] = js
+
+	return js.clone
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+func buildSnapshotCache(nodes, podsPerNode int) *SchedulerCache {
+	cache := &SchedulerCache{
+		Nodes:        make(map[string]*api.NodeInfo),
+		Jobs:         make(map[api.JobID]*api.JobInfo),
+		Queues:       make(map[api.QueueID]*api.QueueInfo),
+		defaultQueue: "default",
+	}
+	cache.AddQueue(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       kbv1.QueueSpec{Weight: 1},
+	})
+
+	for i := 0; i < nodes; i++ {
+		nodeName := fmt.Sprintf("n%d", i)
+		cache.AddNode(buildNode(nodeName, buildResourceList("64000m", "256G")))
+
+		owner := buildOwnerReference(fmt.Sprintf("j%d", i))
+		for j := 0; j < podsPerNode; j++ {
+			cache.AddPod(buildPod("c1", fmt.Sprintf("p%d-%d", i, j), nodeName, v1.PodRunning,
+				buildResourceList("1000m", "1G"), []metav1.OwnerReference{owner}, make(map[string]string)))
+		}
+	}
+
+	return cache
+}
+
+func TestSnapshotSharesUnchangedObjects(t *testing.T) {
+	cache := buildSnapshotCache(2, 2)
+
+	first := cache.Snapshot()
+	if len(first.Nodes) != 2 || len(first.Jobs) != 2 {
+		t.Fatalf("expected 2 nodes and 2 jobs in snapshot, got %d nodes and %d jobs",
+			len(first.Nodes), len(first.Jobs))
+	}
+
+	second := cache.Snapshot()
+	for name, node := range first.Nodes {
+		if second.Nodes[name] != node {
+			t.Errorf("expected unchanged node %s to be shared by snapshots", name)
+		}
+	}
+	for uid, job := range first.Jobs {
+		if second.Jobs[uid] != job {
+			t.Errorf("expected unchanged job %s to be shared by snapshots", uid)
+		}
+	}
+
+	// Change the clone of n0 in session, and n1 in cache.
+	pod := buildPod("c1", "p-new", "n0", v1.PodRunning, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{buildOwnerReference("j0")}, make(map[string]string))
+	second.Nodes["n0"].AddTask(api.NewTaskInfo(pod))
+	cache.AddPod(buildPod("c1", "p1-new", "n1", v1.PodRunning, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{buildOwnerReference("j1")}, make(map[string]string)))
+
+	third := cache.Snapshot()
+	for _, name := range []string{"n0", "n1"} {
+		if third.Nodes[name] == second.Nodes[name] {
+			t.Errorf("expected changed node %s to be cloned again", name)
+		}
+	}
+	if len(third.Nodes["n0"].Tasks) != 2 {
+		t.Errorf("expected change in session to be dropped, got %d tasks on n0",
+			len(third.Nodes["n0"].Tasks))
+	}
+	if len(third.Nodes["n1"].Tasks) != 3 {
+		t.Errorf("expected change in cache to be taken, got %d tasks on n1",
+			len(third.Nodes["n1"].Tasks))
+	}
+	if third.Jobs["j0"] != second.Jobs["j0"] {
+		t.Errorf("expected unchanged job j0 to be shared by snapshots")
+	}
+	if third.Jobs["j1"] == second.Jobs["j1"] {
+		t.Errorf("expected changed job j1 to be cloned again")
+	}
+}
+
+func benchmarkSnapshot(b *testing.B, changes int, full bool) {
+	cache := buildSnapshotCache(1000, 20)
+	cache.Snapshot()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		// Mark the nodes and jobs as changed, as if the pods on them were updated.
+		for j := 0; j < changes; j++ {
+			k := (i*changes + j) % len(cache.Nodes)
+			cache.Nodes[fmt.Sprintf("n%d", k)].Generation++
+			cache.Jobs[api.JobID(fmt.Sprintf("j%d", k))].Generation++
+		}
+		if full {
+			cache.snapshots = snapshots{}
+		}
+		b.StartTimer()
+
+		cache.Snapshot()
+	}
+}
+
+// BenchmarkSnapshotFullClone clones all objects for every snapshot, which is
+// the cost before snapshots are shared.
+func BenchmarkSnapshotFullClone(b *testing.B) {
+	benchmarkSnapshot(b, 0, true)
+}
+
+func BenchmarkSnapshotUnchanged(b *testing.B) {
+	benchmarkSnapshot(b, 0, false)
+}
+
+func BenchmarkSnapshotChanged1Percent(b *testing.B) {
+	benchmarkSnapshot(b, 10, false)
+}
+
+func BenchmarkSnapshotChanged10Percent(b *testing.B) {
+	benchmarkSnapshot(b, 100, false)
+}
@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha2"
 	"github.com/kubernetes-sigs/kube-batch/pkg/apis/utils"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 )
@@ -58,3 +59,35 @@ func createShadowPodGroup(pod *v1.Pod) *v1alpha1.PodGroup {
 		},
 	}
 }
+
+// toPodGroup returns the PodGroup of v1alpha1 from the object of either v1alpha1 or v1alpha2.
+func toPodGroup(obj interface{}) (*v1alpha1.PodGroup, bool) {
+	switch t := obj.(type) {
+	case *v1alpha1.PodGroup:
+		return t, true
+	case *v1alpha2.PodGroup:
+		pg := &v1alpha1.PodGroup{}
+		if err := v1alpha2.Convert_v1alpha2_PodGroup_To_v1alpha1_PodGroup(t, pg, nil); err != nil {
+			return nil, false
+		}
+		return pg, true
+	default:
+		return nil, false
+	}
+}
+
+// toQueue returns the Queue of v1alpha1 from the object of either v1alpha1 or v1alpha2.
+func toQueue(obj interface{}) (*v1alpha1.Queue, bool) {
+	switch t := obj.(type) {
+	case *v1alpha1.Queue:
+		return t, true
+	case *v1alpha2.Queue:
+		queue := &v1alpha1.Queue{}
+		if err := v1alpha2.Convert_v1alpha2_Queue_To_v1alpha1_Queue(t, queue, nil); err != nil {
+			return nil, false
+		}
+		return queue, true
+	default:
+		return nil, false
+	}
+}
@@ -22,8 +22,40 @@ type SchedulerConfiguration struct {
 	Actions string `yaml:"actions"`
 	// Tiers defines plugins in different tiers
 	Tiers []Tier `yaml:"tiers"`
+	// Resources defines how resources are accounted
+	Resources ResourceConfiguration `yaml:"resources"`
+	// Configurations defines the arguments of actions
+	Configurations []Configuration `yaml:"configurations"`
 }
 
+// Configuration defines the arguments of an action
+type Configuration struct {
+	// Name is the name of action
+	Name string `yaml:"name"`
+	// Arguments defines the different arguments that can be given to the action
+	Arguments map[string]string `yaml:"arguments"`
+}
+
+// ResourceConfiguration defines how resources are accounted by scheduler
+type ResourceConfiguration struct {
+	// Classes maps resource names to accounting classes, either "gpu" or "scalar";
+	// the resources of "gpu" class are accounted as GPU, e.g. in the dominant
+	// share of DRF, but still fit by their own names. Only "nvidia.com/gpu" is of
+	// "gpu" class by default.
+	Classes map[string]string `yaml:"classes"`
+	// MinDeltas defines the minimal deltas of resources in quantity, e.g. "10m" for cpu
+	// and "10Mi" for memory; the amounts of resource within the delta are regarded as
+	// equal. The delta of a "gpu" class resource applies to the GPU dimension.
+	MinDeltas map[string]string `yaml:"minDeltas"`
+}
+
+const (
+	// GPUResourceClass is the class of resources accounted as GPU
+	GPUResourceClass = "gpu"
+	// ScalarResourceClass is the class of resources accounted as scalar resources
+	ScalarResourceClass = "scalar"
+)
+
 // Tier defines plugin tier
 type Tier struct {
 	Plugins []PluginOption `yaml:"plugins"`
@@ -49,6 +81,11 @@ type PluginOption struct {
 	PredicateDisabled bool `yaml:"disablePredicate"`
 	// NodeOrderDisabled defines whether NodeOrderFn is disabled
 	NodeOrderDisabled bool `yaml:"disableNodeOrder"`
+	// JobEnqueueableDisabled defines whether jobEnqueueableFn is disabled
+	JobEnqueueableDisabled bool `yaml:"disableJobEnqueueable"`
+	// NodeOrderWeight defines the weight of the node scores of plugin, which are normalized
+	// to the range of 0 to 100; it's 1 if not set
+	NodeOrderWeight int `yaml:"nodeOrderWeight"`
 	// Arguments defines the different arguments that can be given to different plugins
 	Arguments map[string]string `yaml:"arguments"`
 }
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+)
+
+// Arguments are the arguments given to an action or a plugin in configuration.
+type Arguments map[string]string
+
+// GetInt sets ptr to the integer value of key; ptr is left unchanged if the key
+// is not found or its value is not an integer.
+func (a Arguments) GetInt(ptr *int, key string) {
+	if ptr == nil {
+		return
+	}
+
+	arg, found := a[key]
+	if !found || len(arg) == 0 {
+		return
+	}
+
+	value, err := strconv.Atoi(arg)
+	if err != nil {
+		glog.Warningf("Could not parse argument %s: %s as integer, ignore it: %v", key, arg, err)
+		return
+	}
+
+	*ptr = value
+}
+
+// ArgumentType is the type of the value of an argument.
+type ArgumentType string
+
+const (
+	// IntArgument is the type of integer arguments
+	IntArgument ArgumentType = "int"
+	// FloatArgument is the type of floating-point arguments
+	FloatArgument ArgumentType = "float"
+	// BoolArgument is the type of boolean arguments
+	BoolArgument ArgumentType = "bool"
+	// StringArgument is the type of string arguments
+	StringArgument ArgumentType = "string"
+)
+
+// ArgumentSpec describes an argument accepted by an action or a plugin.
+type ArgumentSpec struct {
+	// Name is the key of argument in configuration
+	Name string
+	// Type is the type of the value of argument
+	Type ArgumentType
+	// Description documents the meaning of argument
+	Description string
+}
+
+// validate checks whether the value is of the type of argument.
+func (spec ArgumentSpec) validate(value string) error {
+	var err error
+	switch spec.Type {
+	case IntArgument:
+		_, err = strconv.Atoi(value)
+	case FloatArgument:
+		_, err = strconv.ParseFloat(value, 64)
+	case BoolArgument:
+		_, err = strconv.ParseBool(value)
+	case StringArgument:
+	default:
+		return fmt.Errorf("unknown type %s of argument %s", spec.Type, spec.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q of %s argument %s", value, spec.Type, spec.Name)
+	}
+	return nil
+}
+
+// Validate checks the arguments against the specs; an error is returned for each
+// argument which is not in the specs, or whose value is not of the type in its spec.
+func (a Arguments) Validate(specs []ArgumentSpec) []error {
+	known := map[string]ArgumentSpec{}
+	for _, spec := range specs {
+		known[spec.Name] = spec
+	}
+
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		spec, found := known[key]
+		if !found {
+			errs = append(errs, fmt.Errorf("unknown argument %s", key))
+			continue
+		}
+		if err := spec.validate(a[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// GetArgOfActionFromConf returns the arguments of the action in configurations.
+func GetArgOfActionFromConf(configurations []conf.Configuration, actionName string) Arguments {
+	for _, c := range configurations {
+		if c.Name == actionName {
+			return c.Arguments
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+// disrupt consumes the disruption budgets covering the task; if any of them is
+// exhausted, an error is returned and no budget is consumed, so the callers, e.g.
+// preempt and reclaim, can pick other victims.
+func (ssn *Session) disrupt(task *api.TaskInfo) error {
+	var budgets []*api.DisruptionBudgetInfo
+	for _, budget := range ssn.disruptionBudgets {
+		if !budget.Covers(task) {
+			continue
+		}
+		if budget.Allowed <= 0 {
+			return fmt.Errorf("evicting Task <%s/%s> violates %v",
+				task.Namespace, task.Name, budget)
+		}
+		budgets = append(budgets, budget)
+	}
+
+	for _, budget := range budgets {
+		budget.Allowed--
+		glog.V(4).Infof("Task <%s/%s> disrupted, %v",
+			task.Namespace, task.Name, budget)
+	}
+
+	return nil
+}
+
+// undisrupt gives back the disruption budgets consumed by the task.
+func (ssn *Session) undisrupt(task *api.TaskInfo) {
+	for _, budget := range ssn.disruptionBudgets {
+		if budget.Covers(task) {
+			budget.Allowed++
+		}
+	}
+}
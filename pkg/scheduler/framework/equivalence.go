@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"k8s.io/api/core/v1"
+	hashutil "k8s.io/kubernetes/pkg/util/hash"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+// equivalencePod is the part of pod which the resource-independent predicates
+// depend on; the pods of the same equivalencePod get the same predicate results
+// on a node. The labels are part of it, as the anti-affinity of existing pods
+// is matched against them.
+type equivalencePod struct {
+	Namespace    string
+	Labels       map[string]string
+	NodeSelector map[string]string
+	Affinity     *v1.Affinity
+	Tolerations  []v1.Toleration
+	HostPorts    []v1.ContainerPort
+}
+
+// equivalenceClass returns the hash of equivalencePod of the task; false is returned
+// if the results of the task can not be shared, e.g. its predicates depend on the
+// pods on other nodes by inter-pod affinity.
+func equivalenceClass(task *api.TaskInfo) (uint64, bool) {
+	pod := task.Pod
+	if pod == nil || hasPodAffinity(pod) {
+		return 0, false
+	}
+
+	ep := &equivalencePod{
+		Namespace:    pod.Namespace,
+		Labels:       pod.Labels,
+		NodeSelector: pod.Spec.NodeSelector,
+		Affinity:     pod.Spec.Affinity,
+		Tolerations:  pod.Spec.Tolerations,
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				ep.HostPorts = append(ep.HostPorts, port)
+			}
+		}
+	}
+
+	hash := fnv.New64a()
+	hashutil.DeepHashObject(hash, ep)
+	return hash.Sum64(), true
+}
+
+func hasPodAffinity(pod *v1.Pod) bool {
+	affinity := pod.Spec.Affinity
+	return affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil)
+}
+
+// equivalenceKey is the key of a predicate result; the node generation is part of
+// the key, so the results are invalidated once the node is changed in session,
+// e.g. by Allocate, Pipeline or Evict.
+type equivalenceKey struct {
+	plugin     string
+	class      uint64
+	node       string
+	generation int64
+}
+
+// equivalenceCache caches the results of resource-independent predicates by the
+// equivalence class of tasks; it's scoped to a session, and safe to be used by
+// the predicates called in parallel.
+type equivalenceCache struct {
+	sync.RWMutex
+
+	classes map[api.TaskID]uint64
+	results map[equivalenceKey]error
+}
+
+func newEquivalenceCache() *equivalenceCache {
+	return &equivalenceCache{
+		classes: map[api.TaskID]uint64{},
+		results: map[equivalenceKey]error{},
+	}
+}
+
+// classOf returns the equivalence class of the task, which is calculated once per task.
+func (ec *equivalenceCache) classOf(task *api.TaskInfo) (uint64, bool) {
+	ec.RLock()
+	class, found := ec.classes[task.UID]
+	ec.RUnlock()
+	if found {
+		return class, true
+	}
+
+	class, ok := equivalenceClass(task)
+	if !ok {
+		return 0, false
+	}
+
+	ec.Lock()
+	ec.classes[task.UID] = class
+	ec.Unlock()
+
+	return class, true
+}
+
+// predicate returns the result of fn for the task on the node, which is shared
+// by the tasks of the same equivalence class.
+func (ec *equivalenceCache) predicate(plugin string, fn api.PredicateFn, task *api.TaskInfo, node *api.NodeInfo) error {
+	class, ok := ec.classOf(task)
+	if !ok {
+		return fn(task, node)
+	}
+
+	key := equivalenceKey{
+		plugin:     plugin,
+		class:      class,
+		node:       node.Name,
+		generation: node.Generation,
+	}
+
+	ec.RLock()
+	err, found := ec.results[key]
+	ec.RUnlock()
+	if found {
+		return err
+	}
+
+	err = fn(task, node)
+
+	ec.Lock()
+	ec.results[key] = err
+	ec.Unlock()
+
+	return err
+}
+
+// invalidate drops all results if the task has inter-pod affinity, as it may
+// change the results on the nodes other than its own; the results on its own
+// node are invalidated by the generation of node.
+func (ec *equivalenceCache) invalidate(event *Event) {
+	if event.Task.Pod == nil || !hasPodAffinity(event.Task.Pod) {
+		return
+	}
+
+	ec.Lock()
+	ec.results = map[equivalenceKey]error{}
+	ec.Unlock()
+}
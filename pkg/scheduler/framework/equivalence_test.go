@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+func buildTask(name string, nodeSelector map[string]string, affinity *v1.Affinity) *api.TaskInfo {
+	return api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(name),
+			Name:      name,
+			Namespace: "c1",
+		},
+		Spec: v1.PodSpec{
+			NodeSelector: nodeSelector,
+			Affinity:     affinity,
+		},
+	})
+}
+
+func TestEquivalenceCache(t *testing.T) {
+	calls := 0
+	predicate := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		calls++
+		return nil
+	}
+
+	ec := newEquivalenceCache()
+	node := api.NewNodeInfo(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}})
+	selector := map[string]string{"zone": "a"}
+
+	// Equivalent tasks share the result.
+	ec.predicate("predicates", predicate, buildTask("p1", selector, nil), node)
+	ec.predicate("predicates", predicate, buildTask("p2", selector, nil), node)
+	if calls != 1 {
+		t.Errorf("expected predicate to be called once for equivalent tasks, got %d", calls)
+	}
+
+	// Tasks of different spec don't share the result.
+	ec.predicate("predicates", predicate, buildTask("p3", map[string]string{"zone": "b"}, nil), node)
+	if calls != 2 {
+		t.Errorf("expected predicate to be called for task of different spec, got %d calls", calls)
+	}
+
+	// The results are invalidated once the node is changed.
+	node.AddTask(buildTask("p4", nil, nil))
+	ec.predicate("predicates", predicate, buildTask("p5", selector, nil), node)
+	if calls != 3 {
+		t.Errorf("expected predicate to be called after node changed, got %d calls", calls)
+	}
+
+	// Tasks with inter-pod affinity are not cached.
+	affinity := &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{}}
+	ec.predicate("predicates", predicate, buildTask("p6", selector, affinity), node)
+	ec.predicate("predicates", predicate, buildTask("p7", selector, affinity), node)
+	if calls != 5 {
+		t.Errorf("expected predicate to be called for every task with affinity, got %d calls", calls)
+	}
+
+	// Placing a task with inter-pod affinity drops all results.
+	ec.invalidate(&Event{Task: buildTask("p8", nil, affinity)})
+	ec.predicate("predicates", predicate, buildTask("p9", selector, nil), node)
+	if calls != 6 {
+		t.Errorf("expected predicate to be called after results dropped, got %d calls", calls)
+	}
+}
+
+func TestEquivalenceCacheLabels(t *testing.T) {
+	// The predicate rejects the task whose labels are matched by the anti-affinity
+	// of pods on the node, like the inter-pod affinity predicate.
+	predicate := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		for _, existing := range node.Tasks {
+			affinity := existing.Pod.Spec.Affinity
+			if affinity == nil || affinity.PodAntiAffinity == nil {
+				continue
+			}
+			for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+				selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+				if err != nil {
+					return err
+				}
+				if selector.Matches(labels.Set(task.Pod.Labels)) {
+					return fmt.Errorf("node <%s> didn't match anti-affinity of <%s>", node.Name, existing.Name)
+				}
+			}
+		}
+		return nil
+	}
+
+	ec := newEquivalenceCache()
+	node := api.NewNodeInfo(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}})
+	node.AddTask(buildTask("p1", nil, &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "db"},
+					},
+					TopologyKey: "kubernetes.io/hostname",
+				},
+			},
+		},
+	}))
+
+	// The tasks are equal except the labels, which only one of them is matched by
+	// the anti-affinity of the existing pod.
+	db := buildTask("p2", nil, nil)
+	db.Pod.Labels = map[string]string{"app": "db"}
+	web := buildTask("p3", nil, nil)
+	web.Pod.Labels = map[string]string{"app": "web"}
+
+	if err := ec.predicate("predicates", predicate, db, node); err == nil {
+		t.Errorf("expected task <%s> to be rejected by anti-affinity", db.Name)
+	}
+	if err := ec.predicate("predicates", predicate, web, node); err != nil {
+		t.Errorf("expected task <%s> not to be rejected by anti-affinity, got %v", web.Name, err)
+	}
+}
@@ -26,9 +26,10 @@ import (
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/metrics"
 )
 
-func OpenSession(cache cache.Cache, tiers []conf.Tier) *Session {
+func OpenSession(cache cache.Cache, tiers []conf.Tier, configurations []conf.Configuration) *Session {
 	ssn := openSession(cache)
 	ssn.Tiers = tiers
+	ssn.Configurations = configurations
 
 	for _, tier := range tiers {
 		for _, plugin := range tier.Plugins {
@@ -47,10 +48,15 @@ func OpenSession(cache cache.Cache, tiers []conf.Tier) *Session {
 		metrics.UpdatePluginDuration(plugin.Name(), metrics.OnSessionOpen, metrics.Duration(onSessionOpenStart))
 	}
 
+	ssn.validateJobs()
+
 	return ssn
 }
 
 func CloseSession(ssn *Session) {
+	// Release the resources reserved by plugins before they're closed.
+	ssn.unreserveAll()
+
 	for _, plugin := range ssn.plugins {
 		onSessionCloseStart := time.Now()
 		plugin.OnSessionClose(ssn)
@@ -0,0 +1,393 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/metrics"
+)
+
+// reservedTask is a task whose resources out of cluster are reserved by plugins on the host.
+type reservedTask struct {
+	task     *api.TaskInfo
+	hostname string
+}
+
+// reserve calls the ReserveFns of plugins in order for the task on the host; if any of
+// them fails, the reserved ones are released in reverse order and the error is returned.
+func (ssn *Session) reserve(task *api.TaskInfo, hostname string) error {
+	var reserved []string
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			rfn, found := ssn.reserveFns[plugin.Name]
+			if !found {
+				continue
+			}
+			if err := rfn(task, hostname); err != nil {
+				for i := len(reserved) - 1; i >= 0; i-- {
+					if ufn, found := ssn.unreserveFns[reserved[i]]; found {
+						ufn(task, hostname)
+					}
+				}
+				return fmt.Errorf("plugin %s failed to reserve Task <%s/%s> on <%s>: %v",
+					plugin.Name, task.Namespace, task.Name, hostname, err)
+			}
+			reserved = append(reserved, plugin.Name)
+		}
+	}
+
+	if len(reserved) != 0 {
+		ssn.reservedTasks[task.UID] = &reservedTask{task: task, hostname: hostname}
+	}
+
+	return nil
+}
+
+// unreserve calls the UnreserveFns of plugins in reverse order, if the task is reserved.
+func (ssn *Session) unreserve(task *api.TaskInfo) {
+	ssn.takeReservation(task)()
+}
+
+// takeReservation removes the reservation of task from session, and returns the func
+// to release it by the UnreserveFns of plugins in reverse order; the func can be
+// called out of session.
+func (ssn *Session) takeReservation(task *api.TaskInfo) func() {
+	rt, found := ssn.reservedTasks[task.UID]
+	if !found {
+		return func() {}
+	}
+	delete(ssn.reservedTasks, task.UID)
+
+	var ufns []api.UnreserveFn
+	for i := len(ssn.Tiers) - 1; i >= 0; i-- {
+		plugins := ssn.Tiers[i].Plugins
+		for j := len(plugins) - 1; j >= 0; j-- {
+			if ufn, found := ssn.unreserveFns[plugins[j].Name]; found {
+				ufns = append(ufns, ufn)
+			}
+		}
+	}
+
+	return func() {
+		for _, ufn := range ufns {
+			ufn(rt.task, rt.hostname)
+		}
+	}
+}
+
+// unreserveAll releases the resources of the tasks which are reserved but not dispatched
+// in the session, e.g. the tasks of the jobs which are not ready.
+func (ssn *Session) unreserveAll() {
+	for _, rt := range ssn.reservedTasks {
+		glog.V(3).Infof("Unreserve Task <%s/%s> on <%s> which is not dispatched in Session <%v>",
+			rt.task.Namespace, rt.task.Name, rt.hostname, ssn.UID)
+		ssn.unreserve(rt.task)
+	}
+}
+
+// PermitRejectedError is returned by Session.Allocate if a task of the job is rejected
+// by the PermitFns of plugins; all the tasks allocated to the job are rolled back, so
+// the job should not be allocated again in the session.
+type PermitRejectedError struct {
+	Job api.JobID
+	Err error
+}
+
+func (e *PermitRejectedError) Error() string {
+	return fmt.Sprintf("job <%v> is not permitted: %v", e.Job, e.Err)
+}
+
+// WaitingTask is a task whose binding is delayed by the plugins returning PermitWait;
+// the plugins allow or reject it by the handle from Session.GetWaitingTask.
+type WaitingTask struct {
+	Task *api.TaskInfo
+
+	sync.Mutex
+	// pending is the plugins which have not allowed the task.
+	pending map[string]bool
+	// deadline is the time to reject the task if it's not allowed by all plugins.
+	deadline time.Time
+	// result receives the decision once all plugins allowed the task, or any rejected it.
+	result chan error
+}
+
+func newWaitingTask(task *api.TaskInfo, pending map[string]bool, timeout time.Duration) *WaitingTask {
+	return &WaitingTask{
+		Task:     task,
+		pending:  pending,
+		deadline: time.Now().Add(timeout),
+		result:   make(chan error, 1),
+	}
+}
+
+// Allow records that the plugin allows the task to be bound; the task is bound once
+// all waiting plugins allowed it.
+func (wt *WaitingTask) Allow(plugin string) {
+	wt.Lock()
+	defer wt.Unlock()
+
+	if !wt.pending[plugin] {
+		return
+	}
+	delete(wt.pending, plugin)
+
+	if len(wt.pending) == 0 {
+		wt.decide(nil)
+	}
+}
+
+// Reject rejects the binding of task.
+func (wt *WaitingTask) Reject(plugin, message string) {
+	wt.Lock()
+	defer wt.Unlock()
+
+	wt.decide(fmt.Errorf("plugin %s rejected Task <%s/%s>: %s",
+		plugin, wt.Task.Namespace, wt.Task.Name, message))
+}
+
+// decide sends the decision if it's not made yet; it's called with the lock.
+func (wt *WaitingTask) decide(err error) {
+	select {
+	case wt.result <- err:
+	default:
+	}
+}
+
+// wait returns the decision on the task, or an error if it's not made before deadline.
+func (wt *WaitingTask) wait() error {
+	timer := time.NewTimer(time.Until(wt.deadline))
+	defer timer.Stop()
+
+	select {
+	case err := <-wt.result:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("failed to permit Task <%s/%s> before %v",
+			wt.Task.Namespace, wt.Task.Name, wt.deadline)
+	}
+}
+
+// waitDecisions waits for the decisions on the tasks in order; the first rejection,
+// or timeout, is returned.
+func waitDecisions(waiting []*WaitingTask) error {
+	for _, wt := range waiting {
+		glog.V(3).Infof("Waiting for permit of Task <%s/%s> until %v",
+			wt.Task.Namespace, wt.Task.Name, wt.deadline)
+		if err := wt.wait(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitingTasks is the tasks waiting for permit, indexed by task.
+type waitingTasks struct {
+	sync.Mutex
+	tasks map[api.TaskID]*WaitingTask
+}
+
+func newWaitingTasks() *waitingTasks {
+	return &waitingTasks{
+		tasks: map[api.TaskID]*WaitingTask{},
+	}
+}
+
+// remove removes the waiting tasks, if they're not replaced by a later permit of the tasks.
+func (wts *waitingTasks) remove(waiting []*WaitingTask) {
+	wts.Lock()
+	defer wts.Unlock()
+
+	for _, wt := range waiting {
+		if wts.tasks[wt.Task.UID] == wt {
+			delete(wts.tasks, wt.Task.UID)
+		}
+	}
+}
+
+// schedulerWaitingTasks is the tasks waiting for permit in scheduler; the tasks keep
+// waiting after the session which permits them is closed.
+var schedulerWaitingTasks = newWaitingTasks()
+
+// GetWaitingTask returns the handle of the task waiting for permit, or nil if the
+// task is not waiting; the task may be permitted by an earlier session. It's safe
+// to be called from other goroutines.
+func (ssn *Session) GetWaitingTask(uid api.TaskID) *WaitingTask {
+	ssn.waitingTasks.Lock()
+	defer ssn.waitingTasks.Unlock()
+
+	return ssn.waitingTasks.tasks[uid]
+}
+
+// permitTask calls the PermitFns of plugins for the task; a WaitingTask is returned
+// if any plugin delays the binding, and an error if any plugin rejects it.
+func (ssn *Session) permitTask(task *api.TaskInfo) (*WaitingTask, error) {
+	pending := map[string]bool{}
+	var timeout time.Duration
+
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			pfn, found := ssn.permitFns[plugin.Name]
+			if !found {
+				continue
+			}
+
+			result := pfn(task)
+			if result == nil {
+				continue
+			}
+
+			switch result.Code {
+			case api.PermitReject:
+				return nil, fmt.Errorf("plugin %s rejected Task <%s/%s>: %s",
+					plugin.Name, task.Namespace, task.Name, result.Message)
+			case api.PermitWait:
+				// Wait for the shortest timeout of plugins.
+				if len(pending) == 0 || result.Timeout < timeout {
+					timeout = result.Timeout
+				}
+				pending[plugin.Name] = true
+			}
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	wt := newWaitingTask(task, pending, timeout)
+
+	ssn.waitingTasks.Lock()
+	ssn.waitingTasks.tasks[task.UID] = wt
+	ssn.waitingTasks.Unlock()
+
+	return wt, nil
+}
+
+// permit checks whether the tasks of a job can be bound; an error is returned if any
+// task is rejected by plugins, and the tasks delayed by plugins are returned without
+// waiting for the decisions.
+func (ssn *Session) permit(tasks []*api.TaskInfo) ([]*WaitingTask, error) {
+	var waiting []*WaitingTask
+	for _, task := range tasks {
+		wt, err := ssn.permitTask(task)
+		if err != nil {
+			ssn.waitingTasks.remove(waiting)
+			return nil, err
+		}
+		if wt != nil {
+			waiting = append(waiting, wt)
+		}
+	}
+
+	return waiting, nil
+}
+
+// permittingJob is the tasks of a job handed off to wait for permit out of session;
+// the tasks are assumed on their hosts in cache, so the resources are held for them
+// by the following sessions.
+type permittingJob struct {
+	cache cache.Cache
+	// waitingTasks is the tasks waiting for permit in scheduler.
+	waitingTasks *waitingTasks
+
+	tasks   []*api.TaskInfo
+	waiting []*WaitingTask
+	// unreserves releases the resources reserved by plugins for the tasks.
+	unreserves []func()
+}
+
+// handOff assumes the tasks of job in cache, and waits for the decisions on the
+// waiting tasks in background, so the session is not blocked by plugins. The tasks
+// are bound together once all of them are permitted; otherwise, they're moved back
+// to Pending and their reservations of plugins are released.
+func (ssn *Session) handOff(tasks []*api.TaskInfo, waiting []*WaitingTask) error {
+	pj := &permittingJob{
+		cache:        ssn.cache,
+		waitingTasks: ssn.waitingTasks,
+		tasks:        tasks,
+		waiting:      waiting,
+	}
+
+	for i, task := range tasks {
+		if err := ssn.cache.Assume(task, task.NodeName); err != nil {
+			glog.Errorf("Failed to assume Task <%v/%v> on <%v>: %v",
+				task.Namespace, task.Name, task.NodeName, err)
+			for _, assumed := range tasks[:i] {
+				ssn.cache.ForgetAssumed(assumed)
+			}
+			for _, task := range tasks {
+				ssn.deallocate(task)
+			}
+			ssn.waitingTasks.remove(waiting)
+			return err
+		}
+	}
+
+	for _, task := range tasks {
+		pj.unreserves = append(pj.unreserves, ssn.takeReservation(task))
+
+		if job, found := ssn.Jobs[task.Job]; found {
+			if err := job.UpdateTaskStatus(task, api.Binding); err != nil {
+				glog.Errorf("Failed to update task <%v/%v> status to %v in Session <%v>: %v",
+					task.Namespace, task.Name, api.Binding, ssn.UID, err)
+			}
+		}
+	}
+
+	go pj.run()
+
+	return nil
+}
+
+// run waits for the decisions on the tasks, and binds or gives back the tasks.
+func (pj *permittingJob) run() {
+	defer pj.waitingTasks.remove(pj.waiting)
+
+	err := waitDecisions(pj.waiting)
+	if err == nil {
+		for _, task := range pj.tasks {
+			if err = pj.cache.BindVolumes(task); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		glog.Errorf("Failed to permit Tasks of Job <%v>: %v", pj.tasks[0].Job, err)
+		for i, task := range pj.tasks {
+			pj.cache.ForgetAssumed(task)
+			pj.unreserves[i]()
+		}
+		return
+	}
+
+	for i, task := range pj.tasks {
+		if err := pj.cache.BindAssumed(task); err != nil {
+			glog.Errorf("Failed to bind Task <%v/%v>: %v", task.Namespace, task.Name, err)
+			pj.unreserves[i]()
+			continue
+		}
+		metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
+	}
+}
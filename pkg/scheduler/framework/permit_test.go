@@ -0,0 +1,354 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+)
+
+func newPermitSession(plugins ...string) *Session {
+	tier := conf.Tier{}
+	for _, name := range plugins {
+		tier.Plugins = append(tier.Plugins, conf.PluginOption{Name: name})
+	}
+
+	return &Session{
+		Tiers:         []conf.Tier{tier},
+		reserveFns:    map[string]api.ReserveFn{},
+		unreserveFns:  map[string]api.UnreserveFn{},
+		permitFns:     map[string]api.PermitFn{},
+		reservedTasks: map[api.TaskID]*reservedTask{},
+		waitingTasks:  newWaitingTasks(),
+	}
+}
+
+func TestReserve(t *testing.T) {
+	ssn := newPermitSession("p1", "p2", "p3")
+
+	var calls []string
+	for _, name := range []string{"p1", "p2", "p3"} {
+		name := name
+		ssn.AddReserveFn(name, func(task *api.TaskInfo, hostname string) error {
+			calls = append(calls, "reserve "+name)
+			if name == "p3" && task.Name == "t2" {
+				return fmt.Errorf("no licence")
+			}
+			return nil
+		})
+		ssn.AddUnreserveFn(name, func(task *api.TaskInfo, hostname string) {
+			calls = append(calls, "unreserve "+name)
+		})
+	}
+
+	t1 := &api.TaskInfo{UID: "t1", Name: "t1"}
+	if err := ssn.reserve(t1, "n1"); err != nil {
+		t.Fatalf("expected t1 to be reserved, got %v", err)
+	}
+	ssn.unreserveAll()
+
+	t2 := &api.TaskInfo{UID: "t2", Name: "t2"}
+	if err := ssn.reserve(t2, "n1"); err == nil {
+		t.Fatalf("expected reservation of t2 to fail")
+	}
+	// t2 is already released, and not released again.
+	ssn.unreserve(t2)
+
+	expected := []string{
+		"reserve p1", "reserve p2", "reserve p3",
+		"unreserve p3", "unreserve p2", "unreserve p1",
+		"reserve p1", "reserve p2", "reserve p3",
+		"unreserve p2", "unreserve p1",
+	}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestPermit(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *api.PermitResult
+		decide  func(wt *WaitingTask)
+		permits bool
+	}{
+		{
+			name:    "allow",
+			result:  &api.PermitResult{Code: api.PermitAllow},
+			permits: true,
+		},
+		{
+			name:    "reject",
+			result:  &api.PermitResult{Code: api.PermitReject, Message: "no licence"},
+			permits: false,
+		},
+		{
+			name:    "wait and allow",
+			result:  &api.PermitResult{Code: api.PermitWait, Timeout: time.Minute},
+			decide:  func(wt *WaitingTask) { wt.Allow("p1") },
+			permits: true,
+		},
+		{
+			name:    "wait and reject",
+			result:  &api.PermitResult{Code: api.PermitWait, Timeout: time.Minute},
+			decide:  func(wt *WaitingTask) { wt.Reject("p1", "no licence") },
+			permits: false,
+		},
+		{
+			name:    "wait until timeout",
+			result:  &api.PermitResult{Code: api.PermitWait, Timeout: 10 * time.Millisecond},
+			permits: false,
+		},
+	}
+
+	for _, test := range tests {
+		ssn := newPermitSession("p1", "p2")
+		task := &api.TaskInfo{UID: "t1", Name: "t1"}
+
+		result := test.result
+		ssn.AddPermitFn("p1", func(task *api.TaskInfo) *api.PermitResult {
+			return result
+		})
+		// Plugins without decision allow the task.
+		ssn.AddPermitFn("p2", func(task *api.TaskInfo) *api.PermitResult {
+			return nil
+		})
+
+		if test.decide != nil {
+			decide := test.decide
+			go func() {
+				for {
+					if wt := ssn.GetWaitingTask(task.UID); wt != nil {
+						decide(wt)
+						return
+					}
+					time.Sleep(time.Millisecond)
+				}
+			}()
+		}
+
+		waiting, err := ssn.permit([]*api.TaskInfo{task})
+		if err == nil {
+			err = waitDecisions(waiting)
+			ssn.waitingTasks.remove(waiting)
+		}
+		if test.permits != (err == nil) {
+			t.Errorf("case %s: expected permitted %v, got error %v", test.name, test.permits, err)
+		}
+		if wt := ssn.GetWaitingTask(task.UID); wt != nil {
+			t.Errorf("case %s: expected no waiting task after permit", test.name)
+		}
+	}
+}
+
+// waitingPlugin delays the binding of all tasks, and the job is ready once all
+// of its tasks are allocated.
+type waitingPlugin struct {
+	sync.Mutex
+	unreserved int
+}
+
+func (wp *waitingPlugin) Name() string { return "waiting" }
+func (wp *waitingPlugin) OnSessionOpen(ssn *Session) {
+	ssn.AddJobReadyFn(wp.Name(), func(obj interface{}) bool {
+		job := obj.(*api.JobInfo)
+		return len(job.TaskStatusIndex[api.Allocated]) == len(job.Tasks)
+	})
+	ssn.AddReserveFn(wp.Name(), func(task *api.TaskInfo, hostname string) error {
+		return nil
+	})
+	ssn.AddUnreserveFn(wp.Name(), func(task *api.TaskInfo, hostname string) {
+		wp.Lock()
+		defer wp.Unlock()
+		wp.unreserved++
+	})
+	ssn.AddPermitFn(wp.Name(), func(task *api.TaskInfo) *api.PermitResult {
+		return &api.PermitResult{Code: api.PermitWait, Timeout: 10 * time.Second}
+	})
+}
+func (wp *waitingPlugin) OnSessionClose(ssn *Session) {}
+
+type recordedBinder struct {
+	sync.Mutex
+	binds []string
+}
+
+func (rb *recordedBinder) Bind(p *v1.Pod, hostname string) error {
+	rb.Lock()
+	defer rb.Unlock()
+	rb.binds = append(rb.binds, fmt.Sprintf("%v/%v", p.Name, hostname))
+	return nil
+}
+
+type fakeStatusUpdater struct{}
+
+func (fsu *fakeStatusUpdater) UpdatePodCondition(pod *v1.Pod, podCondition *v1.PodCondition) (*v1.Pod, error) {
+	return nil, nil
+}
+func (fsu *fakeStatusUpdater) UpdatePodGroup(pg *v1alpha1.PodGroup) (*v1alpha1.PodGroup, error) {
+	return nil, nil
+}
+func (fsu *fakeStatusUpdater) UpdateQueueStatus(queue *v1alpha1.Queue) (*v1alpha1.Queue, error) {
+	return nil, nil
+}
+
+type fakeVolumeBinder struct{}
+
+func (fvb *fakeVolumeBinder) AllocateVolumes(task *api.TaskInfo, hostname string) error {
+	return nil
+}
+func (fvb *fakeVolumeBinder) BindVolumes(task *api.TaskInfo) error {
+	return nil
+}
+
+func buildPermitPod(name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(name),
+			Name:      name,
+			Namespace: "c1",
+			Annotations: map[string]string{
+				v1alpha1.GroupNameAnnotationKey: "pg1",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+		},
+	}
+}
+
+func TestAllocateWaitingForPermit(t *testing.T) {
+	plugin := &waitingPlugin{}
+	RegisterPluginBuilder(plugin.Name(), func(map[string]string) Plugin { return plugin })
+	defer CleanupPluginBuilders()
+
+	for _, allow := range []bool{true, false} {
+		plugin.unreserved = 0
+		binder := &recordedBinder{}
+		schedulerCache := &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Binder:        binder,
+			StatusUpdater: &fakeStatusUpdater{},
+			VolumeBinder:  &fakeVolumeBinder{},
+			Recorder:      record.NewFakeRecorder(100),
+		}
+		schedulerCache.AddNode(&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			},
+		})
+		schedulerCache.AddPod(buildPermitPod("p1"))
+		schedulerCache.AddPod(buildPermitPod("p2"))
+		schedulerCache.AddPodGroup(&v1alpha1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "c1"},
+			Spec:       v1alpha1.PodGroupSpec{MinMember: 2, Queue: "c1"},
+		})
+		schedulerCache.AddQueue(&v1alpha1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+			Spec:       v1alpha1.QueueSpec{Weight: 1},
+		})
+
+		ssn := OpenSession(schedulerCache, []conf.Tier{
+			{Plugins: []conf.PluginOption{{Name: plugin.Name()}}},
+		}, nil)
+
+		// The session is not blocked by the tasks waiting for permit.
+		var tasks []*api.TaskInfo
+		for _, task := range ssn.Jobs["c1/pg1"].Tasks {
+			tasks = append(tasks, task)
+		}
+		for _, task := range tasks {
+			if err := ssn.Allocate(task, "n1"); err != nil {
+				t.Fatalf("allow %v: failed to allocate task %s: %v", allow, task.Name, err)
+			}
+		}
+		CloseSession(ssn)
+
+		// The tasks hold the resources in cache while waiting.
+		schedulerCache.Mutex.Lock()
+		for _, task := range schedulerCache.Jobs["c1/pg1"].Tasks {
+			if task.Status != api.Binding {
+				t.Errorf("allow %v: expected task %s to be in %v while waiting, got %v",
+					allow, task.Name, api.Binding, task.Status)
+			}
+		}
+		schedulerCache.Mutex.Unlock()
+
+		for _, task := range tasks {
+			wt := ssn.GetWaitingTask(task.UID)
+			if wt == nil {
+				t.Fatalf("allow %v: expected task %s to be waiting for permit", allow, task.Name)
+			}
+			if allow {
+				wt.Allow(plugin.Name())
+			} else {
+				wt.Reject(plugin.Name(), "no licence")
+			}
+		}
+
+		err := wait.Poll(10*time.Millisecond, 3*time.Second, func() (bool, error) {
+			if allow {
+				binder.Lock()
+				defer binder.Unlock()
+				return len(binder.binds) == 2, nil
+			}
+
+			schedulerCache.Mutex.Lock()
+			defer schedulerCache.Mutex.Unlock()
+			plugin.Lock()
+			defer plugin.Unlock()
+			for _, task := range schedulerCache.Jobs["c1/pg1"].Tasks {
+				if task.Status != api.Pending {
+					return false, nil
+				}
+			}
+			return plugin.unreserved == 2, nil
+		})
+		if err != nil {
+			t.Errorf("allow %v: expected tasks to be bound or given back after decision: %v", allow, err)
+		}
+	}
+}
@@ -37,6 +37,7 @@ func CleanupPluginBuilders() {
 	defer pluginMutex.Unlock()
 
 	pluginBuilders = map[string]PluginBuilder{}
+	pluginArguments = map[string][]ArgumentSpec{}
 }
 
 func GetPluginBuilder(name string) (PluginBuilder, bool) {
@@ -47,6 +48,26 @@ func GetPluginBuilder(name string) (PluginBuilder, bool) {
 	return pb, found
 }
 
+// pluginArguments is the arguments accepted by plugins, indexed by plugin name.
+var pluginArguments = map[string][]ArgumentSpec{}
+
+// RegisterPluginArguments registers the arguments accepted by the plugin; the plugin
+// accepts no argument in configuration if none is registered.
+func RegisterPluginArguments(name string, specs ...ArgumentSpec) {
+	pluginMutex.Lock()
+	defer pluginMutex.Unlock()
+
+	pluginArguments[name] = append(pluginArguments[name], specs...)
+}
+
+// GetPluginArguments returns the arguments accepted by the plugin.
+func GetPluginArguments(name string) []ArgumentSpec {
+	pluginMutex.Lock()
+	defer pluginMutex.Unlock()
+
+	return pluginArguments[name]
+}
+
 // Action management
 var actionMap = map[string]Action{}
 
@@ -64,3 +85,23 @@ func GetAction(name string) (Action, bool) {
 	act, found := actionMap[name]
 	return act, found
 }
+
+// actionArguments is the arguments accepted by actions, indexed by action name.
+var actionArguments = map[string][]ArgumentSpec{}
+
+// RegisterActionArguments registers the arguments accepted by the action; the action
+// accepts no argument in configuration if none is registered.
+func RegisterActionArguments(name string, specs ...ArgumentSpec) {
+	pluginMutex.Lock()
+	defer pluginMutex.Unlock()
+
+	actionArguments[name] = append(actionArguments[name], specs...)
+}
+
+// GetActionArguments returns the arguments accepted by the action.
+func GetActionArguments(name string) []ArgumentSpec {
+	pluginMutex.Lock()
+	defer pluginMutex.Unlock()
+
+	return actionArguments[name]
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api/helpers"
+)
+
+// reserveResources holds the idle resources of nodes for the owners of reservations;
+// the reservations are handled by creation time, and the nodes by name.
+func (ssn *Session) reserveResources() {
+	now := time.Now()
+
+	reservations := make([]*api.ReservationInfo, 0, len(ssn.Reservations))
+	for _, r := range ssn.Reservations {
+		if r.Expired(now) {
+			glog.V(4).Infof("Reservation <%s> expired at %v, ignore it.", r.UID, r.Expiration)
+			continue
+		}
+		reservations = append(reservations, r)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		li := reservations[i].Reservation.CreationTimestamp
+		ri := reservations[j].Reservation.CreationTimestamp
+		if !li.Equal(&ri) {
+			return li.Before(&ri)
+		}
+		return reservations[i].UID < reservations[j].UID
+	})
+
+	nodes := make([]*api.NodeInfo, 0, len(ssn.Nodes))
+	for _, node := range ssn.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	// The resources of nodes which are not reserved yet.
+	unreserved := map[string]*api.Resource{}
+	for _, node := range nodes {
+		unreserved[node.Name] = node.Idle.Clone()
+	}
+
+	for _, r := range reservations {
+		remaining := r.Resreq.Clone()
+		// The resources allocated to the owner were reserved for it.
+		if job, found := ssn.Jobs[r.Owner]; found {
+			remaining.Sub(helpers.Min(remaining, job.Allocated))
+		}
+
+		for _, node := range nodes {
+			if remaining.IsEmpty() {
+				break
+			}
+			if node.Node == nil || !r.NodeSelector.Matches(labels.Set(node.Node.Labels)) {
+				continue
+			}
+
+			reserved := helpers.Min(remaining, unreserved[node.Name])
+			if reserved.IsEmpty() {
+				continue
+			}
+			unreserved[node.Name].Sub(reserved)
+			remaining.Sub(reserved)
+
+			if _, found := ssn.reserved[node.Name]; !found {
+				ssn.reserved[node.Name] = map[api.JobID]*api.Resource{}
+			}
+			if owned, found := ssn.reserved[node.Name][r.Owner]; found {
+				owned.Add(reserved)
+			} else {
+				ssn.reserved[node.Name][r.Owner] = reserved
+			}
+
+			glog.V(4).Infof("Reserved <%v> on Node <%s> for Job <%s> by Reservation <%s>",
+				reserved, node.Name, r.Owner, r.UID)
+		}
+	}
+}
+
+// Reserved returns the resources reserved on the node for the job; it's nil if
+// no resource is reserved.
+func (ssn *Session) Reserved(job api.JobID, hostname string) *api.Resource {
+	return ssn.reserved[hostname][job]
+}
+
+// IdleFor returns the idle resources of node which can be used by the task, that's
+// the idle resources of node except the ones reserved for other jobs.
+func (ssn *Session) IdleFor(task *api.TaskInfo, node *api.NodeInfo) *api.Resource {
+	idle := node.Idle.Clone()
+	for owner, reserved := range ssn.reserved[node.Name] {
+		if owner == task.Job {
+			continue
+		}
+		idle.Sub(helpers.Min(idle, reserved))
+	}
+	return idle
+}
+
+// ReservedForOthers returns whether resources are reserved on the node for the jobs
+// other than the job of task.
+func (ssn *Session) ReservedForOthers(task *api.TaskInfo, node *api.NodeInfo) bool {
+	for owner, reserved := range ssn.reserved[node.Name] {
+		if owner != task.Job && !reserved.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// consumeReservation releases the resources reserved for the job of task,
+// as they're used by the task now.
+func (ssn *Session) consumeReservation(task *api.TaskInfo, hostname string) {
+	reserved, found := ssn.reserved[hostname][task.Job]
+	if !found {
+		return
+	}
+
+	consumed := helpers.Min(reserved, task.Resreq)
+	reserved.Sub(consumed)
+	if reserved.IsEmpty() {
+		delete(ssn.reserved[hostname], task.Job)
+	}
+	ssn.consumed[task.UID] = consumed
+}
+
+// restoreReservation gives back the reserved resources consumed by the task, once
+// its allocation is rolled back.
+func (ssn *Session) restoreReservation(task *api.TaskInfo, hostname string) {
+	consumed, found := ssn.consumed[task.UID]
+	if !found {
+		return
+	}
+	delete(ssn.consumed, task.UID)
+
+	if _, found := ssn.reserved[hostname]; !found {
+		ssn.reserved[hostname] = map[api.JobID]*api.Resource{}
+	}
+	if reserved, found := ssn.reserved[hostname][task.Job]; found {
+		reserved.Add(consumed)
+	} else {
+		ssn.reserved[hostname][task.Job] = consumed
+	}
+}
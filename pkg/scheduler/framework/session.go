@@ -18,10 +18,13 @@ package framework
 
 import (
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/golang/glog"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -44,6 +47,20 @@ type Session struct {
 	Backlog []*api.JobInfo
 	Tiers   []conf.Tier
 
+	// Configurations are the arguments of actions.
+	Configurations []conf.Configuration
+
+	Reservations map[api.ReservationID]*api.ReservationInfo
+	// reserved is the resources reserved on nodes, indexed by node name and owner.
+	reserved map[string]map[api.JobID]*api.Resource
+	// consumed is the reserved resources used by the tasks allocated in session.
+	consumed map[api.TaskID]*api.Resource
+	// disruptionBudgets is the PodDisruptionBudgets left by the evictions in session.
+	disruptionBudgets []*api.DisruptionBudgetInfo
+
+	// eCache shares the results of cacheable predicates between equivalent tasks.
+	eCache *equivalenceCache
+
 	plugins        map[string]Plugin
 	eventHandlers  []*EventHandler
 	jobOrderFns    map[string]api.CompareFn
@@ -54,19 +71,46 @@ type Session struct {
 	preemptableFns map[string]api.EvictableFn
 	reclaimableFns map[string]api.EvictableFn
 	overusedFns    map[string]api.ValidateFn
+	allocatableFns map[string]api.AllocatableFn
 	jobReadyFns    map[string]api.ValidateFn
 	jobValidFns    map[string]api.ValidateExFn
+
+	jobEnqueueableFns map[string]api.ValidateFn
+	jobEnqueuedFns    map[string]api.NotifyFn
+	jobBackloggedFns  map[string]api.NotifyFn
+
+	// cacheablePredicates is the plugins whose predicates are resource-independent.
+	cacheablePredicates map[string]bool
+
+	preFilterFns  map[string]api.PreFilterFn
+	postFilterFns map[string]api.PostFilterFn
+	nodeMapFns    map[string]api.NodeMapFn
+	nodeReduceFns map[string]api.NodeReduceFn
+
+	reserveFns   map[string]api.ReserveFn
+	unreserveFns map[string]api.UnreserveFn
+	permitFns    map[string]api.PermitFn
+	// reservedTasks is the tasks reserved by plugins, which are not dispatched yet.
+	reservedTasks map[api.TaskID]*reservedTask
+	// waitingTasks is the tasks waiting for the permit of plugins in scheduler.
+	waitingTasks *waitingTasks
 }
 
-func openSession(cache cache.Cache) *Session {
-	ssn := &Session{
-		UID:   uuid.NewUUID(),
-		cache: cache,
+// newSession returns a session without jobs, nodes and queues.
+func newSession() *Session {
+	return &Session{
+		UID: uuid.NewUUID(),
 
 		Jobs:   map[api.JobID]*api.JobInfo{},
 		Nodes:  map[string]*api.NodeInfo{},
 		Queues: map[api.QueueID]*api.QueueInfo{},
 
+		Reservations: map[api.ReservationID]*api.ReservationInfo{},
+		reserved:     map[string]map[api.JobID]*api.Resource{},
+		consumed:     map[api.TaskID]*api.Resource{},
+
+		eCache: newEquivalenceCache(),
+
 		plugins:        map[string]Plugin{},
 		jobOrderFns:    map[string]api.CompareFn{},
 		queueOrderFns:  map[string]api.CompareFn{},
@@ -76,36 +120,63 @@ func openSession(cache cache.Cache) *Session {
 		preemptableFns: map[string]api.EvictableFn{},
 		reclaimableFns: map[string]api.EvictableFn{},
 		overusedFns:    map[string]api.ValidateFn{},
+		allocatableFns: map[string]api.AllocatableFn{},
 		jobReadyFns:    map[string]api.ValidateFn{},
 		jobValidFns:    map[string]api.ValidateExFn{},
+
+		jobEnqueueableFns: map[string]api.ValidateFn{},
+		jobEnqueuedFns:    map[string]api.NotifyFn{},
+		jobBackloggedFns:  map[string]api.NotifyFn{},
+
+		cacheablePredicates: map[string]bool{},
+
+		preFilterFns:  map[string]api.PreFilterFn{},
+		postFilterFns: map[string]api.PostFilterFn{},
+		nodeMapFns:    map[string]api.NodeMapFn{},
+		nodeReduceFns: map[string]api.NodeReduceFn{},
+
+		reserveFns:    map[string]api.ReserveFn{},
+		unreserveFns:  map[string]api.UnreserveFn{},
+		permitFns:     map[string]api.PermitFn{},
+		reservedTasks: map[api.TaskID]*reservedTask{},
+		waitingTasks:  schedulerWaitingTasks,
 	}
+}
+
+func openSession(cache cache.Cache) *Session {
+	ssn := newSession()
+	ssn.cache = cache
 
 	snapshot := cache.Snapshot()
 
 	ssn.Jobs = snapshot.Jobs
-	for _, job := range ssn.Jobs {
-		if vjr := ssn.JobValid(job); vjr != nil {
-			if !vjr.Pass {
-				jc := &v1alpha1.PodGroupCondition{
-					Type:               v1alpha1.PodGroupUnschedulableType,
-					Status:             v1.ConditionTrue,
-					LastTransitionTime: metav1.Now(),
-					TransitionID:       string(ssn.UID),
-					Reason:             vjr.Reason,
-					Message:            vjr.Message,
-				}
-
-				if err := ssn.UpdateJobCondition(job, jc); err != nil {
-					glog.Errorf("Failed to update job condition: %v", err)
-				}
-			}
+	ssn.Nodes = snapshot.Nodes
+	ssn.Queues = snapshot.Queues
+	if snapshot.Reservations != nil {
+		ssn.Reservations = snapshot.Reservations
+	}
+	ssn.disruptionBudgets = snapshot.DisruptionBudgets
 
+	for _, job := range ssn.Jobs {
+		// Do not schedule the jobs which have failed.
+		if job.PodGroup != nil && job.PodGroup.Status.Phase == v1alpha1.PodGroupFailed {
 			delete(ssn.Jobs, job.UID)
+			continue
+		}
+
+		// Do not schedule new jobs in the queue which is not open.
+		if queue, found := ssn.Queues[job.Queue]; found && !queueOpen(queue) && !jobStarted(job) {
+			ssn.backlogJob(job, v1alpha1.QueueClosedReason,
+				fmt.Sprintf("Queue <%s> is closed for new PodGroups", queue.Name))
 		}
 	}
 
-	ssn.Nodes = snapshot.Nodes
-	ssn.Queues = snapshot.Queues
+	ssn.reserveResources()
+
+	ssn.AddEventHandler(&EventHandler{
+		AllocateFunc:   ssn.eCache.invalidate,
+		DeallocateFunc: ssn.eCache.invalidate,
+	})
 
 	glog.V(3).Infof("Open Session %v with <%d> Job and <%d> Queues",
 		ssn.UID, len(ssn.Jobs), len(ssn.Queues))
@@ -113,8 +184,52 @@ func openSession(cache cache.Cache) *Session {
 	return ssn
 }
 
+// validateJobs moves the jobs which are not valid for scheduling to Backlog; it's
+// called after plugins are opened, so the JobValidFns of plugins are applied, and
+// the plugins are notified of the backlogged jobs to stop accounting them.
+func (ssn *Session) validateJobs() {
+	for _, job := range ssn.Jobs {
+		if vjr := ssn.JobValid(job); vjr != nil && !vjr.Pass {
+			glog.V(3).Infof("Job <%s/%s> is not valid for scheduling: %s",
+				job.Namespace, job.Name, vjr.Message)
+			ssn.backlogJob(job, vjr.Reason, vjr.Message)
+			ssn.JobBacklogged(job)
+		}
+	}
+}
+
+// backlogJob records the reason why the job will not be scheduled in this session,
+// and moves it from Jobs to Backlog.
+func (ssn *Session) backlogJob(job *api.JobInfo, reason, message string) {
+	jc := &v1alpha1.PodGroupCondition{
+		Type:               v1alpha1.PodGroupUnschedulableType,
+		Status:             v1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		TransitionID:       string(ssn.UID),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	if err := ssn.UpdateJobCondition(job, jc); err != nil {
+		glog.Errorf("Failed to update job condition: %v", err)
+	}
+
+	ssn.Backlog = append(ssn.Backlog, job)
+	delete(ssn.Jobs, job.UID)
+}
+
 func closeSession(ssn *Session) {
+	queueStatuses := map[api.QueueID]*v1alpha1.QueueStatus{}
+	for _, queue := range ssn.Queues {
+		queueStatuses[queue.UID] = &v1alpha1.QueueStatus{}
+	}
+
+	jobs := ssn.Backlog
 	for _, job := range ssn.Jobs {
+		jobs = append(jobs, job)
+	}
+
+	for _, job := range jobs {
 		// If job is using PDB, ignore it.
 		// TODO(k82cn): remove it when removing PDB support
 		if job.PodGroup == nil {
@@ -122,15 +237,44 @@ func closeSession(ssn *Session) {
 			continue
 		}
 
-		job.PodGroup.Status = jobStatus(ssn, job)
+		status := jobStatus(ssn, job)
+		job.PodGroup.Status = status
 		if _, err := ssn.cache.UpdateJobStatus(job); err != nil {
 			glog.Errorf("Failed to update job <%s/%s>: %v",
 				job.Namespace, job.Name, err)
 		}
+
+		if qs, found := queueStatuses[job.Queue]; found {
+			switch status.Phase {
+			case v1alpha1.PodGroupPending:
+				qs.Pending++
+			case v1alpha1.PodGroupInqueue:
+				qs.Inqueue++
+			case v1alpha1.PodGroupRunning:
+				qs.Running++
+			case v1alpha1.PodGroupUnknown:
+				qs.Unknown++
+			}
+		}
+	}
+
+	for _, queue := range ssn.Queues {
+		status := queueStatus(queue, queueStatuses[queue.UID])
+		if reflect.DeepEqual(queue.Queue.Status, status) {
+			continue
+		}
+
+		// Update a copy of Queue to avoid modifying the one in cache.
+		queue.Queue = queue.Queue.DeepCopy()
+		queue.Queue.Status = status
+		if err := ssn.cache.UpdateQueueStatus(queue); err != nil {
+			glog.Errorf("Failed to update queue <%s>: %v", queue.Name, err)
+		}
 	}
 
 	ssn.Jobs = nil
 	ssn.Nodes = nil
+	ssn.Queues = nil
 	ssn.Backlog = nil
 	ssn.plugins = nil
 	ssn.eventHandlers = nil
@@ -166,20 +310,122 @@ func jobStatus(ssn *Session, jobInfo *api.JobInfo) v1alpha1.PodGroupStatus {
 		}
 
 		// If there're enough allocated resource, it's running
-		if int32(allocated) > jobInfo.PodGroup.Spec.MinMember {
+		if allocated != 0 && int32(allocated) >= jobInfo.PodGroup.Spec.MinMember {
+			if status.Phase != v1alpha1.PodGroupRunning && status.Phase != v1alpha1.PodGroupUnknown {
+				now := metav1.Now()
+				status.MinAvailableSatisfiedTime = &now
+			}
 			status.Phase = v1alpha1.PodGroupRunning
-		} else {
+		} else if status.Phase != v1alpha1.PodGroupInqueue {
+			// Keep the admitted job in queue until it's running.
 			status.Phase = v1alpha1.PodGroupPending
 		}
 	}
 
+	switch status.Phase {
+	case v1alpha1.PodGroupRunning:
+		// Reset unschedulable condition, so the unschedulable duration is
+		// counted from the next time it's unschedulable.
+		status.Conditions = resetUnschedulable(ssn, status.Conditions)
+	case v1alpha1.PodGroupPending, v1alpha1.PodGroupInqueue:
+		if !jobStarted(jobInfo) {
+			status = scheduleTimeout(jobInfo.PodGroup.Spec.ScheduleTimeoutSeconds, status)
+		}
+	}
+
 	status.Running = int32(len(jobInfo.TaskStatusIndex[api.Running]))
 	status.Failed = int32(len(jobInfo.TaskStatusIndex[api.Failed]))
 	status.Succeeded = int32(len(jobInfo.TaskStatusIndex[api.Succeeded]))
+	status.Pending = int32(len(jobInfo.TaskStatusIndex[api.Pending]))
+	status.Allocated = jobInfo.Allocated.ResourceList()
+
+	// Only record the session which changed the status, so the PodGroups are not
+	// changed by every session.
+	if !equality.Semantic.DeepEqual(status, jobInfo.PodGroup.Status) {
+		status.SessionID = string(ssn.UID)
+	}
 
 	return status
 }
 
+// resetUnschedulable returns a copy of conditions with the unschedulable condition set to false.
+func resetUnschedulable(ssn *Session, conditions []v1alpha1.PodGroupCondition) []v1alpha1.PodGroupCondition {
+	var res []v1alpha1.PodGroupCondition
+	for _, c := range conditions {
+		if c.Type == v1alpha1.PodGroupUnschedulableType && c.Status == v1.ConditionTrue {
+			c.Status = v1.ConditionFalse
+			c.LastTransitionTime = metav1.Now()
+			c.TransitionID = string(ssn.UID)
+			c.Reason = ""
+			c.Message = ""
+		}
+		res = append(res, c)
+	}
+
+	return res
+}
+
+// scheduleTimeout fails the job if it has been unschedulable for longer than timeout seconds.
+func scheduleTimeout(timeout *int32, status v1alpha1.PodGroupStatus) v1alpha1.PodGroupStatus {
+	if timeout == nil {
+		return status
+	}
+
+	for i, c := range status.Conditions {
+		if c.Type != v1alpha1.PodGroupUnschedulableType || c.Status != v1.ConditionTrue {
+			continue
+		}
+
+		if time.Since(c.LastTransitionTime.Time) <= time.Duration(*timeout)*time.Second {
+			break
+		}
+
+		// Update a copy of conditions to avoid modifying the ones in cache.
+		conditions := make([]v1alpha1.PodGroupCondition, len(status.Conditions))
+		copy(conditions, status.Conditions)
+		conditions[i].Reason = v1alpha1.ScheduleTimeoutReason
+		conditions[i].Message = fmt.Sprintf("PodGroup has been unschedulable for more than %d seconds: %s",
+			*timeout, c.Message)
+
+		status.Conditions = conditions
+		status.Phase = v1alpha1.PodGroupFailed
+		break
+	}
+
+	return status
+}
+
+func queueStatus(queue *api.QueueInfo, counts *v1alpha1.QueueStatus) v1alpha1.QueueStatus {
+	status := *counts
+
+	if queueOpen(queue) {
+		status.State = v1alpha1.QueueStateOpen
+	} else if status.Running+status.Unknown != 0 {
+		// Wait for running PodGroups to finish.
+		status.State = v1alpha1.QueueStateClosing
+	} else {
+		status.State = v1alpha1.QueueStateClosed
+	}
+
+	return status
+}
+
+// queueOpen checks whether new jobs in the queue can be scheduled.
+func queueOpen(queue *api.QueueInfo) bool {
+	return queue.Queue.Spec.State != v1alpha1.QueueStateClosed
+}
+
+// jobStarted checks whether any task of the job has been allocated resources.
+func jobStarted(job *api.JobInfo) bool {
+	for status := range job.TaskStatusIndex {
+		if api.AllocatedStatus(status) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ssn *Session) Statement() *Statement {
 	return &Statement{
 		ssn: ssn,
@@ -233,17 +479,25 @@ func (ssn *Session) Allocate(task *api.TaskInfo, hostname string) error {
 		return err
 	}
 
+	if err := ssn.reserve(task, hostname); err != nil {
+		glog.Errorf("Failed to reserve Task <%v/%v> on <%v> in Session <%v>: %v",
+			task.Namespace, task.Name, hostname, ssn.UID, err)
+		return err
+	}
+
 	// Only update status in session
 	job, found := ssn.Jobs[task.Job]
 	if found {
 		if err := job.UpdateTaskStatus(task, api.Allocated); err != nil {
 			glog.Errorf("Failed to update task <%v/%v> status to %v in Session <%v>: %v",
 				task.Namespace, task.Name, api.Allocated, ssn.UID, err)
+			ssn.unreserve(task)
 			return err
 		}
 	} else {
 		glog.Errorf("Failed to found Job <%s> in Session <%s> index when binding.",
 			task.Job, ssn.UID)
+		ssn.unreserve(task)
 		return fmt.Errorf("failed to find job %s", task.Job)
 	}
 
@@ -253,13 +507,16 @@ func (ssn *Session) Allocate(task *api.TaskInfo, hostname string) error {
 		if err := node.AddTask(task); err != nil {
 			glog.Errorf("Failed to add task <%v/%v> to node <%v> in Session <%v>: %v",
 				task.Namespace, task.Name, hostname, ssn.UID, err)
+			ssn.unreserve(task)
 			return err
 		}
+		ssn.consumeReservation(task, hostname)
 		glog.V(3).Infof("After allocated Task <%v/%v> to Node <%v>: idle <%v>, used <%v>, releasing <%v>",
 			task.Namespace, task.Name, node.Name, node.Idle, node.Used, node.Releasing)
 	} else {
 		glog.Errorf("Failed to found Node <%s> in Session <%s> index when binding.",
 			hostname, ssn.UID)
+		ssn.unreserve(task)
 		return fmt.Errorf("failed to find node %s", hostname)
 	}
 
@@ -273,7 +530,27 @@ func (ssn *Session) Allocate(task *api.TaskInfo, hostname string) error {
 	}
 
 	if ssn.JobReady(job) {
+		var tasks []*api.TaskInfo
 		for _, task := range job.TaskStatusIndex[api.Allocated] {
+			tasks = append(tasks, task)
+		}
+
+		// Bind the tasks of job together once they're permitted, or give back their resources.
+		waiting, err := ssn.permit(tasks)
+		if err != nil {
+			glog.Errorf("Failed to permit Job <%v/%v> in Session <%v>: %v",
+				job.Namespace, job.Name, ssn.UID, err)
+			for _, task := range tasks {
+				ssn.deallocate(task)
+			}
+			return &PermitRejectedError{Job: job.UID, Err: err}
+		}
+
+		if len(waiting) != 0 {
+			return ssn.handOff(tasks, waiting)
+		}
+
+		for _, task := range tasks {
 			if err := ssn.dispatch(task); err != nil {
 				glog.Errorf("Failed to dispatch task <%v/%v>: %v",
 					task.Namespace, task.Name, err)
@@ -287,12 +564,16 @@ func (ssn *Session) Allocate(task *api.TaskInfo, hostname string) error {
 
 func (ssn *Session) dispatch(task *api.TaskInfo) error {
 	if err := ssn.cache.BindVolumes(task); err != nil {
+		ssn.unreserve(task)
 		return err
 	}
 
 	if err := ssn.cache.Bind(task, task.NodeName); err != nil {
+		ssn.unreserve(task)
 		return err
 	}
+	// The reserved resources are kept for the task once it's dispatched.
+	delete(ssn.reservedTasks, task.UID)
 
 	// Update status in session
 	if job, found := ssn.Jobs[task.Job]; found {
@@ -311,8 +592,43 @@ func (ssn *Session) dispatch(task *api.TaskInfo) error {
 	return nil
 }
 
+// deallocate gives back the resources allocated to the task in session, including
+// the ones reserved by plugins.
+func (ssn *Session) deallocate(task *api.TaskInfo) {
+	ssn.unreserve(task)
+	ssn.restoreReservation(task, task.NodeName)
+
+	if job, found := ssn.Jobs[task.Job]; found {
+		if err := job.UpdateTaskStatus(task, api.Pending); err != nil {
+			glog.Errorf("Failed to update task <%v/%v> status to %v in Session <%v>: %v",
+				task.Namespace, task.Name, api.Pending, ssn.UID, err)
+		}
+	}
+
+	if node, found := ssn.Nodes[task.NodeName]; found {
+		if err := node.RemoveTask(task); err != nil {
+			glog.Errorf("Failed to remove task <%v/%v> from node <%v> in Session <%v>: %v",
+				task.Namespace, task.Name, task.NodeName, ssn.UID, err)
+		}
+	}
+	task.NodeName = ""
+
+	for _, eh := range ssn.eventHandlers {
+		if eh.DeallocateFunc != nil {
+			eh.DeallocateFunc(&Event{
+				Task: task,
+			})
+		}
+	}
+}
+
 func (ssn *Session) Evict(reclaimee *api.TaskInfo, reason string) error {
+	if err := ssn.disrupt(reclaimee); err != nil {
+		return err
+	}
+
 	if err := ssn.cache.Evict(reclaimee, reason); err != nil {
+		ssn.undisrupt(reclaimee)
 		return err
 	}
 
@@ -369,6 +685,10 @@ func (ssn *Session) UpdateJobCondition(jobInfo *api.JobInfo, cond *v1alpha1.PodG
 	if index < 0 {
 		job.PodGroup.Status.Conditions = append(job.PodGroup.Status.Conditions, *cond)
 	} else {
+		// Keep the time of the last transition if the status is not changed.
+		if job.PodGroup.Status.Conditions[index].Status == cond.Status {
+			cond.LastTransitionTime = job.PodGroup.Status.Conditions[index].LastTransitionTime
+		}
 		job.PodGroup.Status.Conditions[index] = *cond
 	}
 
@@ -17,7 +17,10 @@ limitations under the License.
 package framework
 
 import (
+	"fmt"
+
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
 )
 
 func (ssn *Session) AddJobOrderFn(name string, cf api.CompareFn) {
@@ -44,22 +47,105 @@ func (ssn *Session) AddJobReadyFn(name string, vf api.ValidateFn) {
 	ssn.jobReadyFns[name] = vf
 }
 
+// AddPredicateFn adds the PredicateFn of plugin; the fn is called for nodes in parallel,
+// so it must be goroutine-safe and must not change the session.
 func (ssn *Session) AddPredicateFn(name string, pf api.PredicateFn) {
 	ssn.predicateFns[name] = pf
 }
 
+// AddCacheablePredicateFn adds the PredicateFn of plugin, whose result is shared by
+// equivalent tasks on a node; the fn must be resource-independent, i.e. its result only
+// depends on the pod spec of task and the node. As the error is returned for all the
+// equivalent tasks, it must not name the task. It's also called in parallel.
+func (ssn *Session) AddCacheablePredicateFn(name string, pf api.PredicateFn) {
+	ssn.predicateFns[name] = pf
+	ssn.cacheablePredicates[name] = true
+}
+
+// AddPreFilterFn adds the PreFilterFn of plugin, which is called once for a task before
+// its nodes are predicated by actions, e.g. to prepare the state of task shared by its
+// PredicateFn and NodeOrderFn calls.
+func (ssn *Session) AddPreFilterFn(name string, fn api.PreFilterFn) {
+	ssn.preFilterFns[name] = fn
+}
+
+// AddPostFilterFn adds the PostFilterFn of plugin, which is called once for a task with
+// its feasible nodes after predicates, before the nodes are scored.
+func (ssn *Session) AddPostFilterFn(name string, fn api.PostFilterFn) {
+	ssn.postFilterFns[name] = fn
+}
+
+// AddNodeOrderFn adds the NodeOrderFn of plugin; the fn is called for nodes in parallel,
+// so it must be goroutine-safe and must not change the session. It's the same as the
+// NodeMapFn of plugin, whose scores are normalized.
 func (ssn *Session) AddNodeOrderFn(name string, pf api.NodeOrderFn) {
 	ssn.nodeOrderFns[name] = pf
 }
 
+// AddNodeMapFn adds the NodeMapFn of plugin; the fn is called for nodes in parallel, so
+// it must be goroutine-safe and must not change the session.
+func (ssn *Session) AddNodeMapFn(name string, fn api.NodeMapFn) {
+	ssn.nodeMapFns[name] = fn
+}
+
+// AddNodeReduceFn adds the NodeReduceFn of plugin, which is called once with the scores
+// of all feasible nodes by the NodeMapFn of plugin, or zero scores if the plugin has no
+// NodeMapFn, e.g. to score the nodes relative to each other.
+func (ssn *Session) AddNodeReduceFn(name string, fn api.NodeReduceFn) {
+	ssn.nodeReduceFns[name] = fn
+}
+
+// AddReserveFn adds the ReserveFn of plugin, which is called when the task is allocated to
+// the host in session; the reserved resources are released by the UnreserveFn of plugin if
+// the task is not dispatched to bind, e.g. its job is not ready or not permitted.
+func (ssn *Session) AddReserveFn(name string, fn api.ReserveFn) {
+	ssn.reserveFns[name] = fn
+}
+
+// AddUnreserveFn adds the UnreserveFn of plugin; it's only called for the tasks reserved
+// by the ReserveFn of plugin, and may be called after the session is closed if the
+// task is waiting for permit.
+func (ssn *Session) AddUnreserveFn(name string, fn api.UnreserveFn) {
+	ssn.unreserveFns[name] = fn
+}
+
+// AddPermitFn adds the PermitFn of plugin, which is called before the tasks of a ready job
+// are dispatched to bind; if it returns PermitWait, the plugin allows or rejects the task
+// later by the handle from GetWaitingTask. The session does not wait for the decision:
+// the tasks of job hold their resources in cache while waiting, and are bound together
+// once all of them are permitted.
+func (ssn *Session) AddPermitFn(name string, fn api.PermitFn) {
+	ssn.permitFns[name] = fn
+}
+
 func (ssn *Session) AddOverusedFn(name string, fn api.ValidateFn) {
 	ssn.overusedFns[name] = fn
 }
 
+// AddAllocatableFn adds the AllocatableFn of plugin, which is called before a task is
+// allocated resources in its queue.
+func (ssn *Session) AddAllocatableFn(name string, fn api.AllocatableFn) {
+	ssn.allocatableFns[name] = fn
+}
+
 func (ssn *Session) AddJobValidFn(name string, fn api.ValidateExFn) {
 	ssn.jobValidFns[name] = fn
 }
 
+func (ssn *Session) AddJobEnqueueableFn(name string, fn api.ValidateFn) {
+	ssn.jobEnqueueableFns[name] = fn
+}
+
+func (ssn *Session) AddJobEnqueuedFn(name string, fn api.NotifyFn) {
+	ssn.jobEnqueuedFns[name] = fn
+}
+
+// AddJobBackloggedFn adds the JobBackloggedFn of plugin, which is called when a job is
+// moved to Backlog after plugins are opened, e.g. to stop accounting its requests.
+func (ssn *Session) AddJobBackloggedFn(name string, fn api.NotifyFn) {
+	ssn.jobBackloggedFns[name] = fn
+}
+
 func (ssn *Session) Reclaimable(reclaimer *api.TaskInfo, reclaimees []*api.TaskInfo) []*api.TaskInfo {
 	var victims []*api.TaskInfo
 	var init bool
@@ -159,6 +245,24 @@ func (ssn *Session) Overused(queue *api.QueueInfo) bool {
 	return false
 }
 
+// Allocatable checks whether the task can be allocated resources in the queue; it's
+// allocatable only if all plugins agree.
+func (ssn *Session) Allocatable(queue *api.QueueInfo, task *api.TaskInfo) bool {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			af, found := ssn.allocatableFns[plugin.Name]
+			if !found {
+				continue
+			}
+			if !af(queue, task) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func (ssn *Session) JobReady(obj interface{}) bool {
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
@@ -179,6 +283,56 @@ func (ssn *Session) JobReady(obj interface{}) bool {
 	return true
 }
 
+// JobEnqueueable checks whether the job can be admitted; it's admitted only
+// if all plugins agree.
+func (ssn *Session) JobEnqueueable(obj interface{}) bool {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			if plugin.JobEnqueueableDisabled {
+				continue
+			}
+			jef, found := ssn.jobEnqueueableFns[plugin.Name]
+			if !found {
+				continue
+			}
+
+			if !jef(obj) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// JobEnqueued notifies plugins that the job was admitted.
+func (ssn *Session) JobEnqueued(obj interface{}) {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			jef, found := ssn.jobEnqueuedFns[plugin.Name]
+			if !found {
+				continue
+			}
+
+			jef(obj)
+		}
+	}
+}
+
+// JobBacklogged notifies plugins that the job was moved to Backlog.
+func (ssn *Session) JobBacklogged(obj interface{}) {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			jbf, found := ssn.jobBackloggedFns[plugin.Name]
+			if !found {
+				continue
+			}
+
+			jbf(obj)
+		}
+	}
+}
+
 func (ssn *Session) JobValid(obj interface{}) *api.ValidateResult {
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
@@ -284,6 +438,36 @@ func (ssn *Session) TaskOrderFn(l, r interface{}) bool {
 	}
 }
 
+// PreFilter calls the PreFilterFns of plugins for the task before its nodes are predicated;
+// the task should not be placed if an error is returned.
+func (ssn *Session) PreFilter(task *api.TaskInfo) error {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			pfn, found := ssn.preFilterFns[plugin.Name]
+			if !found {
+				continue
+			}
+			if err := pfn(task); err != nil {
+				return fmt.Errorf("plugin %s failed to prefilter Task <%s/%s>: %v",
+					plugin.Name, task.Namespace, task.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// PostFilter calls the PostFilterFns of plugins with the feasible nodes of task, which
+// may be empty, e.g. for plugins to explain why no node is feasible.
+func (ssn *Session) PostFilter(task *api.TaskInfo, nodes []*api.NodeInfo) {
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			if pfn, found := ssn.postFilterFns[plugin.Name]; found {
+				pfn(task, nodes)
+			}
+		}
+	}
+}
+
 func (ssn *Session) PredicateFn(task *api.TaskInfo, node *api.NodeInfo) error {
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
@@ -294,7 +478,12 @@ func (ssn *Session) PredicateFn(task *api.TaskInfo, node *api.NodeInfo) error {
 			if !found {
 				continue
 			}
-			err := pfn(task, node)
+			var err error
+			if ssn.cacheablePredicates[plugin.Name] {
+				err = ssn.eCache.predicate(plugin.Name, pfn, task, node)
+			} else {
+				err = pfn(task, node)
+			}
 			if err != nil {
 				return err
 			}
@@ -303,24 +492,106 @@ func (ssn *Session) PredicateFn(task *api.TaskInfo, node *api.NodeInfo) error {
 	return nil
 }
 
-func (ssn *Session) NodeOrderFn(task *api.TaskInfo, node *api.NodeInfo) (int, error) {
-	priorityScore := 0
+// NodeOrderMapFn returns the scores of the node for the task by plugins, indexed by plugin
+// name; it's called for nodes in parallel.
+func (ssn *Session) NodeOrderMapFn(task *api.TaskInfo, node *api.NodeInfo) (map[string]float64, error) {
+	scores := map[string]float64{}
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if plugin.NodeOrderDisabled {
 				continue
 			}
-			pfn, found := ssn.nodeOrderFns[plugin.Name]
-			if !found {
+			if mfn, found := ssn.nodeMapFns[plugin.Name]; found {
+				score, err := mfn(task, node)
+				if err != nil {
+					return nil, err
+				}
+				scores[plugin.Name] = score
+			} else if pfn, found := ssn.nodeOrderFns[plugin.Name]; found {
+				score, err := pfn(task, node)
+				if err != nil {
+					return nil, err
+				}
+				scores[plugin.Name] = float64(score)
+			}
+		}
+	}
+	return scores, nil
+}
+
+// NodeOrderReduceFn returns the total scores of the nodes for the task, indexed by node name;
+// the scores of each plugin are reduced by its NodeReduceFn, normalized by its max score to
+// the range of 0 to MaxNodeScore, and weighted by its NodeOrderWeight, so the plugins of
+// different score ranges are comparable.
+func (ssn *Session) NodeOrderReduceFn(task *api.TaskInfo, nodes []*api.NodeInfo,
+	pluginScores map[string]map[string]float64) (map[string]float64, error) {
+	totals := map[string]float64{}
+	for _, node := range nodes {
+		totals[node.Name] = 0
+	}
+
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			if plugin.NodeOrderDisabled {
 				continue
 			}
-			score, err := pfn(task, node)
-			if err != nil {
-				return 0, err
-			} else {
-				priorityScore = priorityScore + score
+
+			scores, found := pluginScores[plugin.Name]
+			rfn, reducible := ssn.nodeReduceFns[plugin.Name]
+			if !found && !reducible {
+				continue
+			}
+
+			if !found {
+				scores = map[string]float64{}
+				for _, node := range nodes {
+					scores[node.Name] = 0
+				}
 			}
+			if reducible {
+				if err := rfn(task, scores); err != nil {
+					return nil, fmt.Errorf("plugin %s failed to reduce scores of Task <%s/%s>: %v",
+						plugin.Name, task.Namespace, task.Name, err)
+				}
+			}
+
+			normalizeScores(scores)
+			weight := nodeOrderWeight(plugin)
+			for name, score := range scores {
+				if _, found := totals[name]; found {
+					totals[name] += score * weight
+				}
+			}
+		}
+	}
+
+	return totals, nil
+}
+
+// normalizeScores scales the scores by the max score of plugin to the range of 0 to
+// MaxNodeScore, the same as the default normalization of kube-scheduler; the negative
+// scores are taken as zero, so the scores are all zero if the max score is not positive.
+func normalizeScores(scores map[string]float64) {
+	var max float64
+	for _, score := range scores {
+		if score > max {
+			max = score
+		}
+	}
+
+	for name, score := range scores {
+		if max > 0 && score > 0 {
+			scores[name] = score * api.MaxNodeScore / max
+		} else {
+			scores[name] = 0
 		}
 	}
-	return priorityScore, nil
+}
+
+// nodeOrderWeight returns the weight of node scores of plugin.
+func nodeOrderWeight(plugin conf.PluginOption) float64 {
+	if plugin.NodeOrderWeight > 0 {
+		return float64(plugin.NodeOrderWeight)
+	}
+	return 1
 }
@@ -0,0 +1,176 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+)
+
+func TestNodeOrderReduceFn(t *testing.T) {
+	nodes := []*api.NodeInfo{{Name: "n1"}, {Name: "n2"}, {Name: "n3"}}
+
+	tests := []struct {
+		name     string
+		plugins  []conf.PluginOption
+		expected map[string]float64
+	}{
+		{
+			name: "plugins of different ranges are equal",
+			plugins: []conf.PluginOption{
+				{Name: "small"},
+				{Name: "large"},
+				{Name: "batch"},
+			},
+			expected: map[string]float64{"n1": 200, "n2": 200, "n3": 100},
+		},
+		{
+			name: "weighted plugin wins",
+			plugins: []conf.PluginOption{
+				{Name: "small", NodeOrderWeight: 3},
+				{Name: "large"},
+				{Name: "batch"},
+			},
+			expected: map[string]float64{"n1": 200, "n2": 300, "n3": 300},
+		},
+		{
+			name: "disabled plugin is ignored",
+			plugins: []conf.PluginOption{
+				{Name: "small"},
+				{Name: "large", NodeOrderDisabled: true},
+			},
+			expected: map[string]float64{"n1": 0, "n2": 50, "n3": 100},
+		},
+		{
+			name: "scores are normalized by max",
+			plugins: []conf.PluginOption{
+				{Name: "close"},
+			},
+			expected: map[string]float64{"n1": 80, "n2": 90, "n3": 100},
+		},
+		{
+			name: "zero scores are kept",
+			plugins: []conf.PluginOption{
+				{Name: "zero"},
+			},
+			expected: map[string]float64{"n1": 0, "n2": 0, "n3": 0},
+		},
+		{
+			name: "negative scores are taken as zero",
+			plugins: []conf.PluginOption{
+				{Name: "negative"},
+			},
+			expected: map[string]float64{"n1": 0, "n2": 0, "n3": 100},
+		},
+		{
+			name: "all negative scores are taken as zero",
+			plugins: []conf.PluginOption{
+				{Name: "penalty"},
+			},
+			expected: map[string]float64{"n1": 0, "n2": 0, "n3": 0},
+		},
+	}
+
+	for _, test := range tests {
+		ssn := &Session{
+			Tiers:         []conf.Tier{{Plugins: test.plugins}},
+			nodeOrderFns:  map[string]api.NodeOrderFn{},
+			nodeMapFns:    map[string]api.NodeMapFn{},
+			nodeReduceFns: map[string]api.NodeReduceFn{},
+		}
+		scores := map[string]map[string]float64{
+			"small":    {"n1": 0, "n2": 5, "n3": 10},
+			"large":    {"n1": 10000, "n2": 5000, "n3": 0},
+			"close":    {"n1": 8, "n2": 9, "n3": 10},
+			"zero":     {"n1": 0, "n2": 0, "n3": 0},
+			"negative": {"n1": -10, "n2": 0, "n3": 10},
+			"penalty":  {"n1": -10, "n2": -5, "n3": -1},
+		}
+		ssn.AddNodeMapFn("small", nil)
+		ssn.AddNodeMapFn("large", nil)
+		ssn.AddNodeMapFn("close", nil)
+		ssn.AddNodeMapFn("zero", nil)
+		ssn.AddNodeMapFn("negative", nil)
+		ssn.AddNodeMapFn("penalty", nil)
+		// The batch plugin scores all nodes at once by NodeReduceFn.
+		ssn.AddNodeReduceFn("batch", func(task *api.TaskInfo, scores map[string]float64) error {
+			scores["n1"] = 1
+			scores["n2"] = 1
+			return nil
+		})
+
+		totals, err := ssn.NodeOrderReduceFn(&api.TaskInfo{}, nodes, scores)
+		if err != nil {
+			t.Fatalf("case %s: unexpected error: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.expected, totals) {
+			t.Errorf("case %s: expected %v, got %v", test.name, test.expected, totals)
+		}
+	}
+}
+
+func TestPreFilterAndPostFilter(t *testing.T) {
+	var calls []string
+	ssn := &Session{
+		Tiers: []conf.Tier{
+			{Plugins: []conf.PluginOption{{Name: "p1"}, {Name: "p2"}}},
+			{Plugins: []conf.PluginOption{{Name: "p3"}}},
+		},
+		preFilterFns:  map[string]api.PreFilterFn{},
+		postFilterFns: map[string]api.PostFilterFn{},
+	}
+	for _, name := range []string{"p3", "p2", "p1"} {
+		name := name
+		ssn.AddPreFilterFn(name, func(task *api.TaskInfo) error {
+			calls = append(calls, "pre:"+name)
+			if name == "p2" && task.Name == "rejected" {
+				return fmt.Errorf("task rejected")
+			}
+			return nil
+		})
+		ssn.AddPostFilterFn(name, func(task *api.TaskInfo, nodes []*api.NodeInfo) {
+			calls = append(calls, fmt.Sprintf("post:%s:%d", name, len(nodes)))
+		})
+	}
+
+	// The fns are called in the order of tiers and plugins.
+	task := &api.TaskInfo{Namespace: "c1", Name: "accepted"}
+	if err := ssn.PreFilter(task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ssn.PostFilter(task, []*api.NodeInfo{{Name: "n1"}})
+	expected := []string{"pre:p1", "pre:p2", "pre:p3", "post:p1:1", "post:p2:1", "post:p3:1"}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	// The following plugins are skipped once a plugin rejects the task.
+	calls = nil
+	err := ssn.PreFilter(&api.TaskInfo{Namespace: "c1", Name: "rejected"})
+	if err == nil || !strings.Contains(err.Error(), "plugin p2") {
+		t.Errorf("expected the task to be rejected by plugin p2, got %v", err)
+	}
+	expected = []string{"pre:p1", "pre:p2"}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
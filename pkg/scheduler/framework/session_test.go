@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+func buildStatusTask(name, nodeName string, phase v1.PodPhase) *api.TaskInfo {
+	return api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(name),
+			Name:      name,
+			Namespace: "c1",
+			Annotations: map[string]string{
+				v1alpha1.GroupNameAnnotationKey: "pg1",
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: phase,
+		},
+	})
+}
+
+func buildStatusJob(minMember int32, status v1alpha1.PodGroupStatus, tasks ...*api.TaskInfo) *api.JobInfo {
+	job := api.NewJobInfo("c1/pg1", tasks...)
+	job.SetPodGroup(&v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg1",
+			Namespace: "c1",
+		},
+		Spec: v1alpha1.PodGroupSpec{
+			MinMember: minMember,
+		},
+		Status: status,
+	})
+	return job
+}
+
+func TestJobStatus(t *testing.T) {
+	ssn := newSession()
+
+	// The job gets running once exactly minMember tasks are allocated.
+	job := buildStatusJob(2, v1alpha1.PodGroupStatus{Phase: v1alpha1.PodGroupInqueue},
+		buildStatusTask("p1", "n1", v1.PodRunning),
+		buildStatusTask("p2", "n1", v1.PodRunning),
+		buildStatusTask("p3", "", v1.PodPending))
+	status := jobStatus(ssn, job)
+
+	if status.Phase != v1alpha1.PodGroupRunning {
+		t.Errorf("expected phase %s with minMember tasks allocated, got %s", v1alpha1.PodGroupRunning, status.Phase)
+	}
+	if status.Running != 2 || status.Pending != 1 {
+		t.Errorf("expected 2 running and 1 pending tasks, got %d running and %d pending",
+			status.Running, status.Pending)
+	}
+	if cpu := status.Allocated[v1.ResourceCPU]; cpu.MilliValue() != 2000 {
+		t.Errorf("expected 2 allocated cpu, got %v", cpu.String())
+	}
+	if status.MinAvailableSatisfiedTime == nil {
+		t.Errorf("expected MinAvailableSatisfiedTime to be set once job is running")
+	}
+	if status.SessionID != string(ssn.UID) {
+		t.Errorf("expected SessionID %s of the session changing status, got %s", ssn.UID, status.SessionID)
+	}
+
+	// The status is not changed by the next session if nothing is changed.
+	job.PodGroup.Status = status
+	next := newSession()
+	if unchanged := jobStatus(next, job); unchanged.SessionID != string(ssn.UID) {
+		t.Errorf("expected SessionID %s to be kept for unchanged status, got %s", ssn.UID, unchanged.SessionID)
+	}
+
+	// The job is pending with fewer than minMember tasks allocated.
+	job = buildStatusJob(2, v1alpha1.PodGroupStatus{},
+		buildStatusTask("p1", "n1", v1.PodRunning),
+		buildStatusTask("p2", "", v1.PodPending))
+	status = jobStatus(ssn, job)
+
+	if status.Phase != v1alpha1.PodGroupPending {
+		t.Errorf("expected phase %s with fewer than minMember tasks allocated, got %s",
+			v1alpha1.PodGroupPending, status.Phase)
+	}
+	if status.MinAvailableSatisfiedTime != nil {
+		t.Errorf("expected MinAvailableSatisfiedTime not to be set for pending job")
+	}
+}
+
+func TestDeallocateRestoresReservation(t *testing.T) {
+	ssn := newSession()
+	ssn.reserved["n1"] = map[api.JobID]*api.Resource{
+		"c1/pg1": {MilliCPU: 2000},
+	}
+
+	t1 := buildStatusTask("p1", "n1", v1.PodPending)
+	t1.Job = "c1/pg1"
+	t2 := buildStatusTask("p2", "n1", v1.PodPending)
+	t2.Job = "c1/pg1"
+
+	// The reservation is used up by the tasks.
+	ssn.consumeReservation(t1, "n1")
+	ssn.consumeReservation(t2, "n1")
+	if reserved := ssn.Reserved("c1/pg1", "n1"); reserved != nil {
+		t.Fatalf("expected reservation to be consumed, got %v", reserved)
+	}
+
+	ssn.deallocate(t1)
+	if reserved := ssn.Reserved("c1/pg1", "n1"); reserved == nil || reserved.MilliCPU != 1000 {
+		t.Errorf("expected 1 cpu reservation to be restored, got %v", reserved)
+	}
+
+	ssn.deallocate(t2)
+	if reserved := ssn.Reserved("c1/pg1", "n1"); reserved == nil || reserved.MilliCPU != 2000 {
+		t.Errorf("expected 2 cpu reservation to be restored, got %v", reserved)
+	}
+}
@@ -33,6 +33,10 @@ type operation struct {
 }
 
 func (s *Statement) Evict(reclaimee *api.TaskInfo, reason string) error {
+	if err := s.ssn.disrupt(reclaimee); err != nil {
+		return err
+	}
+
 	// Update status in session
 	job, found := s.ssn.Jobs[reclaimee.Job]
 	if found {
@@ -79,6 +83,8 @@ func (s *Statement) evict(reclaimee *api.TaskInfo, reason string) error {
 }
 
 func (s *Statement) unevict(reclaimee *api.TaskInfo, reason string) error {
+	s.ssn.undisrupt(reclaimee)
+
 	// Update status in session
 	job, found := s.ssn.Jobs[reclaimee.Job]
 	if found {
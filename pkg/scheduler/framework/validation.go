@@ -0,0 +1,229 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+)
+
+// ValidateSchedulerConf checks the actions, tiers and configurations of scheduler
+// configuration: the actions and plugins must be registered and configured once,
+// enqueue must be configured before allocate,
+// the arguments must be registered by the action or plugin with the type of value,
+// and the fns disabled by the options of plugin must be implemented by the plugin.
+// All errors found are returned together.
+func ValidateSchedulerConf(actionNames []string, tiers []conf.Tier, configurations []conf.Configuration) error {
+	var errs []error
+	errs = append(errs, validateActions(actionNames, configurations)...)
+	errs = append(errs, validateTiers(tiers)...)
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func validateActions(actionNames []string, configurations []conf.Configuration) []error {
+	var errs []error
+
+	actions := map[string]bool{}
+	for _, name := range actionNames {
+		if actions[name] {
+			errs = append(errs, fmt.Errorf("action %q is configured more than once", name))
+			continue
+		}
+
+		// The allocate action only allocates resources to the jobs admitted by
+		// enqueue action, so enqueue must be configured before allocate.
+		if name == "allocate" && !actions["enqueue"] {
+			errs = append(errs, fmt.Errorf("action %q must be configured before %q", "enqueue", name))
+		}
+		actions[name] = true
+
+		if _, found := GetAction(name); !found {
+			errs = append(errs, fmt.Errorf("unknown action %q", name))
+		}
+	}
+
+	configured := map[string]bool{}
+	for _, c := range configurations {
+		if configured[c.Name] {
+			errs = append(errs, fmt.Errorf("configuration of action %q is given more than once", c.Name))
+			continue
+		}
+		configured[c.Name] = true
+
+		if _, found := GetAction(c.Name); !found {
+			errs = append(errs, fmt.Errorf("configuration of unknown action %q", c.Name))
+			continue
+		}
+		for _, err := range Arguments(c.Arguments).Validate(GetActionArguments(c.Name)) {
+			errs = append(errs, fmt.Errorf("action %s: %v", c.Name, err))
+		}
+	}
+
+	return errs
+}
+
+func validateTiers(tiers []conf.Tier) []error {
+	var errs []error
+
+	plugins := map[string]bool{}
+	for _, tier := range tiers {
+		for _, option := range tier.Plugins {
+			if plugins[option.Name] {
+				errs = append(errs, fmt.Errorf("plugin %q is configured more than once", option.Name))
+				continue
+			}
+			plugins[option.Name] = true
+
+			pb, found := GetPluginBuilder(option.Name)
+			if !found {
+				errs = append(errs, fmt.Errorf("unknown plugin %q", option.Name))
+				continue
+			}
+
+			var pluginErrs []error
+			pluginErrs = append(pluginErrs, Arguments(option.Arguments).Validate(GetPluginArguments(option.Name))...)
+			if option.NodeOrderWeight < 0 {
+				pluginErrs = append(pluginErrs, fmt.Errorf("negative nodeOrderWeight %d", option.NodeOrderWeight))
+			}
+			pluginErrs = append(pluginErrs, validateDisabledFns(option, pb)...)
+
+			for _, err := range pluginErrs {
+				errs = append(errs, fmt.Errorf("plugin %s: %v", option.Name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// disableOption is an option of plugin to disable its fns in session.
+type disableOption struct {
+	// name is the key of option in configuration
+	name string
+	// disabled returns whether the option is set
+	disabled func(option *conf.PluginOption) bool
+	// implemented returns whether the plugin adds any fn disabled by the option
+	implemented func(ssn *Session, plugin string) bool
+}
+
+var disableOptions = []disableOption{
+	{
+		name:     "disableJobOrder",
+		disabled: func(option *conf.PluginOption) bool { return option.JobOrderDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.jobOrderFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disableJobReady",
+		disabled: func(option *conf.PluginOption) bool { return option.JobReadyDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.jobReadyFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disableTaskOrder",
+		disabled: func(option *conf.PluginOption) bool { return option.TaskOrderDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.taskOrderFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disablePreemptable",
+		disabled: func(option *conf.PluginOption) bool { return option.PreemptableDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.preemptableFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disableReclaimable",
+		disabled: func(option *conf.PluginOption) bool { return option.ReclaimableDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.reclaimableFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disableQueueOrder",
+		disabled: func(option *conf.PluginOption) bool { return option.QueueOrderDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.queueOrderFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disablePredicate",
+		disabled: func(option *conf.PluginOption) bool { return option.PredicateDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.predicateFns[plugin]
+			return found
+		},
+	},
+	{
+		name:     "disableNodeOrder",
+		disabled: func(option *conf.PluginOption) bool { return option.NodeOrderDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, order := ssn.nodeOrderFns[plugin]
+			_, mapped := ssn.nodeMapFns[plugin]
+			_, reduced := ssn.nodeReduceFns[plugin]
+			return order || mapped || reduced
+		},
+	},
+	{
+		name:     "disableJobEnqueueable",
+		disabled: func(option *conf.PluginOption) bool { return option.JobEnqueueableDisabled },
+		implemented: func(ssn *Session, plugin string) bool {
+			_, found := ssn.jobEnqueueableFns[plugin]
+			return found
+		},
+	},
+}
+
+// validateDisabledFns checks that the fns disabled by the options are implemented by
+// the plugin; the plugin is opened on an empty session to find the fns it adds.
+func validateDisabledFns(option conf.PluginOption, pb PluginBuilder) []error {
+	var set []disableOption
+	for _, do := range disableOptions {
+		if do.disabled(&option) {
+			set = append(set, do)
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	ssn := newSession()
+	ssn.Tiers = []conf.Tier{{Plugins: []conf.PluginOption{option}}}
+	plugin := pb(option.Arguments)
+	plugin.OnSessionOpen(ssn)
+
+	var errs []error
+	for _, do := range set {
+		if !do.implemented(ssn, plugin.Name()) {
+			errs = append(errs, fmt.Errorf("%s is set, but the plugin does not implement the fn", do.name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+)
+
+type fakeAction struct {
+	name string
+}
+
+func (fa *fakeAction) Name() string         { return fa.name }
+func (fa *fakeAction) Initialize()          {}
+func (fa *fakeAction) Execute(ssn *Session) {}
+func (fa *fakeAction) UnInitialize()        {}
+
+// fakeOrderPlugin only implements JobOrderFn.
+type fakeOrderPlugin struct{}
+
+func (fp *fakeOrderPlugin) Name() string { return "order" }
+func (fp *fakeOrderPlugin) OnSessionOpen(ssn *Session) {
+	ssn.AddJobOrderFn(fp.Name(), func(l, r interface{}) int { return 0 })
+}
+func (fp *fakeOrderPlugin) OnSessionClose(ssn *Session) {}
+
+func TestValidateSchedulerConf(t *testing.T) {
+	RegisterAction(&fakeAction{name: "act"})
+	RegisterAction(&fakeAction{name: "enqueue"})
+	RegisterAction(&fakeAction{name: "allocate"})
+	RegisterActionArguments("act", ArgumentSpec{Name: "limit", Type: IntArgument})
+	RegisterPluginBuilder("order", func(map[string]string) Plugin { return &fakeOrderPlugin{} })
+	RegisterPluginArguments("order", ArgumentSpec{Name: "enabled", Type: BoolArgument})
+	defer CleanupPluginBuilders()
+
+	tests := []struct {
+		name           string
+		actions        []string
+		plugins        []conf.PluginOption
+		configurations []conf.Configuration
+		valid          bool
+	}{
+		{
+			name:    "valid configuration",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{
+				{Name: "order", JobOrderDisabled: true, Arguments: map[string]string{"enabled": "true"}},
+			},
+			configurations: []conf.Configuration{
+				{Name: "act", Arguments: map[string]string{"limit": "10"}},
+			},
+			valid: true,
+		},
+		{
+			name:    "enqueue before allocate",
+			actions: []string{"enqueue", "act", "allocate"},
+			valid:   true,
+		},
+		{
+			name:    "allocate without enqueue",
+			actions: []string{"allocate", "act"},
+		},
+		{
+			name:    "enqueue after allocate",
+			actions: []string{"allocate", "enqueue"},
+		},
+		{
+			name:    "unknown action",
+			actions: []string{"act", "unknown"},
+		},
+		{
+			name:    "duplicate action",
+			actions: []string{"act", "act"},
+		},
+		{
+			name:    "unknown plugin",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{{Name: "ordr"}},
+		},
+		{
+			name:    "duplicate plugin",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{{Name: "order"}, {Name: "order"}},
+		},
+		{
+			name:    "unknown argument of plugin",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{{Name: "order", Arguments: map[string]string{"enable": "true"}}},
+		},
+		{
+			name:    "invalid value of plugin argument",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{{Name: "order", Arguments: map[string]string{"enabled": "yes"}}},
+		},
+		{
+			name:    "disabled fn not implemented",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{{Name: "order", TaskOrderDisabled: true}},
+		},
+		{
+			name:    "negative nodeOrderWeight",
+			actions: []string{"act"},
+			plugins: []conf.PluginOption{{Name: "order", NodeOrderWeight: -1}},
+		},
+		{
+			name:    "configuration of unknown action",
+			actions: []string{"act"},
+			configurations: []conf.Configuration{
+				{Name: "unknown"},
+			},
+		},
+		{
+			name:    "duplicate configuration of action",
+			actions: []string{"act"},
+			configurations: []conf.Configuration{
+				{Name: "act"},
+				{Name: "act"},
+			},
+		},
+		{
+			name:    "invalid value of action argument",
+			actions: []string{"act"},
+			configurations: []conf.Configuration{
+				{Name: "act", Arguments: map[string]string{"limit": "ten"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateSchedulerConf(test.actions, []conf.Tier{{Plugins: test.plugins}}, test.configurations)
+		if test.valid && err != nil {
+			t.Errorf("case %s: expected valid configuration, got error: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("case %s: expected invalid configuration, got no error", test.name)
+		}
+	}
+}
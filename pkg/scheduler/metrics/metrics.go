@@ -32,6 +32,18 @@ const (
 
 	// OnSessionClose label
 	OnSessionClose = "OnSessionClose"
+
+	// BindSuccess label
+	BindSuccess = "success"
+
+	// BindError label
+	BindError = "error"
+
+	// SchedulerConfLoadSuccess label
+	SchedulerConfLoadSuccess = "success"
+
+	// SchedulerConfLoadError label
+	SchedulerConfLoadError = "error"
 )
 
 var (
@@ -111,6 +123,39 @@ var (
 		},
 	)
 
+	bindResults = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: KubeBatchNamespace,
+			Name:      "bind_results_total",
+			Help:      "Number of binding requests to API server, by the result",
+		}, []string{"result"},
+	)
+
+	bindLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: KubeBatchNamespace,
+			Name:      "bind_latency_milliseconds",
+			Help:      "Binding latency in milliseconds, by the result",
+			Buckets:   prometheus.ExponentialBuckets(5, 2, 10),
+		}, []string{"result"},
+	)
+
+	assumedTaskExpirations = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: KubeBatchNamespace,
+			Name:      "assumed_task_expirations_total",
+			Help:      "Number of tasks expired in Binding and moved back to Pending",
+		},
+	)
+
+	schedulerConfLoads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: KubeBatchNamespace,
+			Name:      "scheduler_conf_loads_total",
+			Help:      "Number of loads of scheduler configuration file, by the result",
+		}, []string{"result"},
+	)
+
 	jobRetryCount = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: KubeBatchNamespace,
@@ -170,6 +215,22 @@ func RegisterJobRetries(jobID string) {
 	jobRetryCount.WithLabelValues(jobID).Inc()
 }
 
+// UpdateBindResult records the result and latency of binding request
+func UpdateBindResult(result string, duration time.Duration) {
+	bindResults.WithLabelValues(result).Inc()
+	bindLatency.WithLabelValues(result).Observe(DurationInMilliseconds(duration))
+}
+
+// RegisterAssumedTaskExpiration records the expiration of assumed task
+func RegisterAssumedTaskExpiration() {
+	assumedTaskExpirations.Inc()
+}
+
+// RegisterSchedulerConfLoad records the result of loading scheduler configuration file
+func RegisterSchedulerConfLoad(result string) {
+	schedulerConfLoads.WithLabelValues(result).Inc()
+}
+
 // DurationInMicroseconds gets the time in microseconds.
 func DurationInMicroseconds(duration time.Duration) float64 {
 	return float64(duration.Nanoseconds()) / float64(time.Microsecond.Nanoseconds())
@@ -26,6 +26,7 @@ import (
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/predicates"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/priority"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/proportion"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins/reservation"
 )
 
 func init() {
@@ -36,7 +37,11 @@ func init() {
 	framework.RegisterPluginBuilder("priority", priority.New)
 	framework.RegisterPluginBuilder("nodeorder", nodeorder.New)
 	framework.RegisterPluginBuilder("conformance", conformance.New)
+	framework.RegisterPluginBuilder("reservation", reservation.New)
 
 	// Plugins for Queues
 	framework.RegisterPluginBuilder("proportion", proportion.New)
+
+	// Arguments of Plugins
+	framework.RegisterPluginArguments("nodeorder", nodeorder.Arguments...)
 }
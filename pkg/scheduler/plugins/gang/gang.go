@@ -90,6 +90,12 @@ func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
 			}
 		}
 
+		// The controllers may create the pods of a gang only after it's admitted,
+		// so the number of tasks is not checked until the gang is Inqueue.
+		if job.IsPending() {
+			return nil
+		}
+
 		vtn := validTaskNum(job)
 		if vtn < job.MinAvailable {
 			return &api.ValidateResult{
@@ -19,6 +19,7 @@ package nodeorder
 import (
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/golang/glog"
 
@@ -31,6 +32,7 @@ import (
 
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/util"
 )
 
 const (
@@ -44,6 +46,30 @@ const (
 	BalancedResourceWeight = "balancedresource.weight"
 )
 
+// Arguments are the arguments accepted by the plugin.
+var Arguments = []framework.ArgumentSpec{
+	{
+		Name:        NodeAffinityWeight,
+		Type:        framework.IntArgument,
+		Description: "The weight of node affinity priority, 1 by default",
+	},
+	{
+		Name:        PodAffinityWeight,
+		Type:        framework.IntArgument,
+		Description: "The weight of inter-pod affinity priority, 1 by default",
+	},
+	{
+		Name:        LeastRequestedWeight,
+		Type:        framework.IntArgument,
+		Description: "The weight of least requested priority, 1 by default",
+	},
+	{
+		Name:        BalancedResourceWeight,
+		Type:        framework.IntArgument,
+		Description: "The weight of balanced resource allocation priority, 1 by default",
+	},
+}
+
 type nodeOrderPlugin struct {
 	// Arguments given for the plugin
 	pluginArguments map[string]string
@@ -179,7 +205,7 @@ func calculateWeight(args map[string]string) priorityWeight {
 	   User Should give priorityWeight in this format(nodeaffinity.weight, podaffinity.weight, leastrequested.weight, balancedresource.weight).
 	   Currently supported only for nodeaffinity, podaffinity, leastrequested, balancedresouce priorities.
 
-	   actions: "reclaim, allocate, backfill, preempt"
+	   actions: "enqueue, reclaim, allocate, backfill, preempt"
 	   tiers:
 	   - plugins:
 	     - name: priority
@@ -248,31 +274,100 @@ func calculateWeight(args map[string]string) priorityWeight {
 	return weight
 }
 
+// taskState is the state of task shared by the NodeOrderFn calls for it; it's prepared
+// once by the PostFilterFn for the task, or by the first NodeOrderFn call if the task
+// is not filtered, and dropped once the session is changed.
+type taskState struct {
+	uid     api.TaskID
+	nodeMap map[string]*cache.NodeInfo
+	// interPodAffinityScore is the scores of inter pod affinity on feasible nodes.
+	interPodAffinityScore schedulerapi.HostPriorityList
+	interPodAffinityErr   error
+}
+
 func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
-	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (int, error) {
+	weight := calculateWeight(pp.pluginArguments)
+
+	pl := &podLister{
+		session: ssn,
+	}
 
-		weight := calculateWeight(pp.pluginArguments)
+	nl := &nodeLister{
+		session: ssn,
+	}
 
-		pl := &podLister{
-			session: ssn,
-		}
+	cn := &cachedNodeInfo{
+		session: ssn,
+	}
 
-		nl := &nodeLister{
-			session: ssn,
-		}
+	interPodAffinityFn := priorities.NewInterPodAffinityPriority(cn, nl, pl, v1.DefaultHardPodAffinitySymmetricWeight)
 
-		cn := &cachedNodeInfo{
-			session: ssn,
+	newTaskState := func(task *api.TaskInfo, nodes []*api.NodeInfo) *taskState {
+		nodeMap, _ := generateNodeMapAndSlice(ssn.Nodes)
+		state := &taskState{
+			uid:     task.UID,
+			nodeMap: nodeMap,
 		}
 
-		var nodeMap map[string]*cache.NodeInfo
 		var nodeSlice []*v1.Node
-		var interPodAffinityScore schedulerapi.HostPriorityList
+		for _, node := range nodes {
+			nodeSlice = append(nodeSlice, node.Node)
+		}
+		state.interPodAffinityScore, state.interPodAffinityErr = interPodAffinityFn(task.Pod, nodeMap, nodeSlice)
 
-		nodeMap, nodeSlice = generateNodeMapAndSlice(ssn.Nodes)
+		return state
+	}
 
-		nodeInfo := cache.NewNodeInfo(node.Pods()...)
-		nodeInfo.SetNode(node.Node)
+	// The node map is generated once for a task, and the inter pod affinity is scored
+	// once on its feasible nodes.
+	var mutex sync.Mutex
+	var state *taskState
+	ssn.AddPreFilterFn(pp.Name(), func(task *api.TaskInfo) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		state = nil
+		return nil
+	})
+	ssn.AddPostFilterFn(pp.Name(), func(task *api.TaskInfo, nodes []*api.NodeInfo) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		state = nil
+		if len(nodes) > 0 {
+			state = newTaskState(task, nodes)
+		}
+	})
+	// The state is stale once tasks are placed or evicted in session.
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc: func(event *framework.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			state = nil
+		},
+		DeallocateFunc: func(event *framework.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			state = nil
+		},
+	})
+	taskStateFor := func(task *api.TaskInfo) *taskState {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if state == nil || state.uid != task.UID {
+			// The task is not filtered by the action, score it on all nodes.
+			state = newTaskState(task, util.GetNodeList(ssn.Nodes))
+		}
+		return state
+	}
+
+	// The priorities only read the session, so it's safe to call them in parallel.
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (int, error) {
+		ts := taskStateFor(task)
+
+		nodeInfo, found := ts.nodeMap[node.Name]
+		if !found {
+			nodeInfo = cache.NewNodeInfo(node.Pods()...)
+			nodeInfo.SetNode(node.Node)
+		}
 		var score = 0
 
 		//TODO: Add ImageLocalityPriority Function once priorityMetadata is published
@@ -302,13 +397,11 @@ func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
 		// If nodeAffinityWeight in provided, host.Score is multiplied with weight, if not, host.Score is added to total score.
 		score = score + (host.Score * weight.nodeAffinityWeight)
 
-		mapFn := priorities.NewInterPodAffinityPriority(cn, nl, pl, v1.DefaultHardPodAffinitySymmetricWeight)
-		interPodAffinityScore, err = mapFn(task.Pod, nodeMap, nodeSlice)
-		if err != nil {
-			glog.Warningf("Calculate Inter Pod Affinity Priority Failed because of Error: %v", err)
-			return 0, err
+		if ts.interPodAffinityErr != nil {
+			glog.Warningf("Calculate Inter Pod Affinity Priority Failed because of Error: %v", ts.interPodAffinityErr)
+			return 0, ts.interPodAffinityErr
 		}
-		hostScore := getInterPodAffinityScore(node.Name, interPodAffinityScore)
+		hostScore := getInterPodAffinityScore(node.Name, ts.interPodAffinityScore)
 		// If podAffinityWeight in provided, host.Score is multiplied with weight, if not, host.Score is added to total score.
 		score = score + (hostScore * weight.podAffinityWeight)
 
@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeorder
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+)
+
+func buildResourceList(cpu string, memory string) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func buildNode(name string, alloc v1.ResourceList) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: v1.NodeStatus{
+			Capacity:    alloc,
+			Allocatable: alloc,
+		},
+	}
+}
+
+func buildPod(ns, n string, req v1.ResourceList, groupName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(fmt.Sprintf("%v-%v", ns, n)),
+			Name:      n,
+			Namespace: ns,
+			Annotations: map[string]string{
+				kbv1.GroupNameAnnotationKey: groupName,
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: req,
+					},
+				},
+			},
+		},
+	}
+}
+
+type fakeStatusUpdater struct {
+}
+
+func (ftsu *fakeStatusUpdater) UpdatePodCondition(pod *v1.Pod, podCondition *v1.PodCondition) (*v1.Pod, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func (ftsu *fakeStatusUpdater) UpdatePodGroup(pg *kbv1.PodGroup) (*kbv1.PodGroup, error) {
+	// do nothing here
+	return nil, nil
+}
+
+func (ftsu *fakeStatusUpdater) UpdateQueueStatus(queue *kbv1.Queue) (*kbv1.Queue, error) {
+	// do nothing here
+	return nil, nil
+}
+
+type fakeBinder struct {
+}
+
+func (fb *fakeBinder) Bind(p *v1.Pod, hostname string) error {
+	return nil
+}
+
+type fakeVolumeBinder struct {
+}
+
+func (fvb *fakeVolumeBinder) AllocateVolumes(task *api.TaskInfo, hostname string) error {
+	return nil
+}
+func (fvb *fakeVolumeBinder) BindVolumes(task *api.TaskInfo) error {
+	return nil
+}
+
+func TestNodeOrderTaskState(t *testing.T) {
+	framework.RegisterPluginBuilder("nodeorder", New)
+	defer framework.CleanupPluginBuilders()
+
+	tests := []struct {
+		name     string
+		filtered bool
+	}{
+		{
+			name:     "filtered task is rescored after session is changed",
+			filtered: true,
+		},
+		{
+			name:     "unfiltered task is rescored after session is changed",
+			filtered: false,
+		},
+	}
+
+	for _, test := range tests {
+		schedulerCache := &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Binder:        &fakeBinder{},
+			StatusUpdater: &fakeStatusUpdater{},
+			VolumeBinder:  &fakeVolumeBinder{},
+
+			Recorder: record.NewFakeRecorder(100),
+		}
+		schedulerCache.AddNode(buildNode("n1", buildResourceList("4", "8G")))
+		schedulerCache.AddNode(buildNode("n2", buildResourceList("4", "8G")))
+		schedulerCache.AddQueue(&kbv1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+			Spec:       kbv1.QueueSpec{Weight: 1},
+		})
+		schedulerCache.AddPod(buildPod("c1", "p1", buildResourceList("1", "1G"), "pg1"))
+		schedulerCache.AddPod(buildPod("c1", "p2", buildResourceList("2", "2G"), "pg1"))
+		schedulerCache.AddPodGroup(&kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pg1",
+				Namespace: "c1",
+			},
+			Spec: kbv1.PodGroupSpec{
+				Queue: "c1",
+			},
+		})
+
+		// Only the least requested priority is scored.
+		ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+			{
+				Plugins: []conf.PluginOption{
+					{
+						Name: "nodeorder",
+						Arguments: map[string]string{
+							LeastRequestedWeight:   "1",
+							BalancedResourceWeight: "0",
+							NodeAffinityWeight:     "0",
+							PodAffinityWeight:      "0",
+						},
+					},
+				},
+			},
+		}, nil)
+
+		job := ssn.Jobs["c1/pg1"]
+		task := job.Tasks["c1-p1"]
+		n1, n2 := ssn.Nodes["n1"], ssn.Nodes["n2"]
+
+		score := func(node *api.NodeInfo) float64 {
+			scores, err := ssn.NodeOrderMapFn(task, node)
+			if err != nil {
+				t.Fatalf("case %s: failed to score node %s: %v", test.name, node.Name, err)
+			}
+			return scores["nodeorder"]
+		}
+
+		if test.filtered {
+			if err := ssn.PreFilter(task); err != nil {
+				t.Fatalf("case %s: failed to prefilter task: %v", test.name, err)
+			}
+			ssn.PostFilter(task, []*api.NodeInfo{n1, n2})
+		}
+		if s1, s2 := score(n1), score(n2); s1 != s2 {
+			t.Errorf("case %s: expected equal scores of idle nodes, got %v and %v", test.name, s1, s2)
+		}
+
+		// The node gets less idle once another task is placed on it.
+		if err := ssn.Allocate(job.Tasks["c1-p2"], "n2"); err != nil {
+			t.Fatalf("case %s: failed to allocate task: %v", test.name, err)
+		}
+		if s1, s2 := score(n1), score(n2); s1 <= s2 {
+			t.Errorf("case %s: expected the score %v of idle node to be higher than %v", test.name, s1, s2)
+		}
+
+		framework.CloseSession(ssn)
+	}
+}
@@ -120,7 +120,10 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 		session: ssn,
 	}
 
-	ssn.AddPredicateFn(pp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) error {
+	// The predicates only read the session, so it's safe to call them in parallel;
+	// and they don't check resources, so the results are shared by equivalent tasks,
+	// which is also why the errors only name the node and the reason.
+	ssn.AddCacheablePredicateFn(pp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) error {
 		nodeInfo := cache.NewNodeInfo(node.Pods()...)
 		nodeInfo.SetNode(node.Node)
 
@@ -138,8 +141,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 			task.Namespace, task.Name, node.Name, fit, err)
 
 		if !fit {
-			return fmt.Errorf("node <%s> didn't match task <%s/%s> node selector",
-				node.Name, task.Namespace, task.Name)
+			return fmt.Errorf("node <%s> didn't match node selector", node.Name)
 		}
 
 		// HostPorts Predicate
@@ -152,8 +154,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 			task.Namespace, task.Name, node.Name, fit, err)
 
 		if !fit {
-			return fmt.Errorf("node <%s> didn't have available host ports for task <%s/%s>",
-				node.Name, task.Namespace, task.Name)
+			return fmt.Errorf("node <%s> didn't have available host ports", node.Name)
 		}
 
 		// Check to see if node.Spec.Unschedulable is set
@@ -166,8 +167,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 			task.Namespace, task.Name, node.Name, fit, err)
 
 		if !fit {
-			return fmt.Errorf("task <%s/%s> node <%s> set to unschedulable",
-				task.Namespace, task.Name, node.Name)
+			return fmt.Errorf("node <%s> set to unschedulable", node.Name)
 		}
 
 		// Toleration/Taint Predicate
@@ -180,8 +180,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 			task.Namespace, task.Name, node.Name, fit, err)
 
 		if !fit {
-			return fmt.Errorf("task <%s/%s> does not tolerate node <%s> taints",
-				task.Namespace, task.Name, node.Name)
+			return fmt.Errorf("node <%s> taints not tolerated", node.Name)
 		}
 
 		// Pod Affinity/Anti-Affinity Predicate
@@ -195,8 +194,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 			task.Namespace, task.Name, node.Name, fit, err)
 
 		if !fit {
-			return fmt.Errorf("task <%s/%s> affinity/anti-affinity failed on node <%s>",
-				node.Name, task.Namespace, task.Name)
+			return fmt.Errorf("node <%s> didn't match affinity/anti-affinity", node.Name)
 		}
 
 		return nil
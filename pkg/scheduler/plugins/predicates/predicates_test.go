@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+)
+
+func buildTask(name string, nodeSelector map[string]string) *api.TaskInfo {
+	return api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(name),
+			Name:      name,
+			Namespace: "c1",
+		},
+		Spec: v1.PodSpec{
+			NodeSelector: nodeSelector,
+		},
+	})
+}
+
+func TestPredicateErrorsOfEquivalentTasks(t *testing.T) {
+	framework.RegisterPluginBuilder("predicates", New)
+	defer framework.CleanupPluginBuilders()
+
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:  make(map[string]*api.NodeInfo),
+		Jobs:   make(map[api.JobID]*api.JobInfo),
+		Queues: make(map[api.QueueID]*api.QueueInfo),
+	}
+	schedulerCache.AddNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourcePods: resource.MustParse("10"),
+			},
+		},
+	})
+
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{Name: "predicates"},
+			},
+		},
+	}, nil)
+	defer framework.CloseSession(ssn)
+
+	// The tasks are equivalent, so the error of p1 is returned for p2 from cache.
+	selector := map[string]string{"zone": "a"}
+	p1 := buildTask("p1", selector)
+	p2 := buildTask("p2", selector)
+	node := ssn.Nodes["n1"]
+
+	err1 := ssn.PredicateFn(p1, node)
+	err2 := ssn.PredicateFn(p2, node)
+	if err1 == nil || err2 == nil {
+		t.Fatalf("expected tasks to be rejected by node selector, got %v and %v", err1, err2)
+	}
+	if err1.Error() != err2.Error() {
+		t.Errorf("expected the same error for equivalent tasks, got <%v> and <%v>", err1, err2)
+	}
+	if strings.Contains(err2.Error(), p1.Name) {
+		t.Errorf("expected error of task <%s> not to name task <%s>, got <%v>", p2.Name, p1.Name, err2)
+	}
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package proportion
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 
+	"k8s.io/api/core/v1"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api/helpers"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
@@ -40,6 +45,16 @@ type queueAttr struct {
 	deserved  *api.Resource
 	allocated *api.Resource
 	request   *api.Resource
+	// inqueue is the minimal resources of admitted jobs which are not allocated yet.
+	inqueue *api.Resource
+	// capability is the upper limit of resources of the queue;
+	// nil means the queue is not limited.
+	capability *api.Resource
+
+	// parent is nil for root queues; the resources of a queue are
+	// also accounted to all of its ancestors.
+	parent   *queueAttr
+	children []*queueAttr
 }
 
 func New(arguments map[string]string) framework.Plugin {
@@ -66,91 +81,111 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 	for _, job := range ssn.Jobs {
 		glog.V(4).Infof("Considering Job <%s/%s>.", job.Namespace, job.Name)
 
-		if _, found := pp.queueOpts[job.Queue]; !found {
-			queue := ssn.Queues[job.Queue]
-			attr := &queueAttr{
-				queueID: queue.UID,
-				name:    queue.Name,
-				weight:  queue.Weight,
+		attr := pp.buildQueueAttr(ssn, job.Queue, map[api.QueueID]bool{})
+		allocated, request, inqueue := jobResources(job)
+
+		// The resources of job are also accounted to the ancestors of its queue.
+		for qa := attr; qa != nil; qa = qa.parent {
+			qa.allocated.Add(allocated)
+			qa.request.Add(request)
+			qa.inqueue.Add(inqueue)
+		}
+	}
+
+	pp.distributeRoots()
 
-				deserved:  api.EmptyResource(),
-				allocated: api.EmptyResource(),
-				request:   api.EmptyResource(),
+	ssn.AddJobValidFn(pp.Name(), func(obj interface{}) *api.ValidateResult {
+		job, ok := obj.(*api.JobInfo)
+		if !ok {
+			return &api.ValidateResult{
+				Pass:    false,
+				Message: fmt.Sprintf("Failed to convert <%v> to *JobInfo", obj),
 			}
-			pp.queueOpts[job.Queue] = attr
-			glog.V(4).Infof("Added Queue <%s> attributes.", job.Queue)
 		}
 
-		for status, tasks := range job.TaskStatusIndex {
-			if api.AllocatedStatus(status) {
-				for _, t := range tasks {
-					attr := pp.queueOpts[job.Queue]
-					attr.allocated.Add(t.Resreq)
-					attr.request.Add(t.Resreq)
-				}
-			} else if status == api.Pending {
-				for _, t := range tasks {
-					attr := pp.queueOpts[job.Queue]
-					attr.request.Add(t.Resreq)
-				}
+		// Only check the jobs which did not get their minimal resources.
+		if job.MinResources == nil || job.MinResources.LessEqual(job.Allocated) {
+			return nil
+		}
+
+		if !job.MinResources.LessEqual(pp.totalResource) {
+			return &api.ValidateResult{
+				Pass:   false,
+				Reason: v1alpha1.ExceedClusterResourcesReason,
+				Message: fmt.Sprintf("The minimal resources <%v> of job exceed the total allocatable resources <%v> of cluster",
+					job.MinResources, pp.totalResource),
 			}
 		}
-	}
 
-	remaining := pp.totalResource.Clone()
-	meet := map[api.QueueID]struct{}{}
-	for {
-		totalWeight := int32(0)
-		for _, attr := range pp.queueOpts {
-			if _, found := meet[attr.queueID]; found {
-				continue
+		if attr, found := pp.queueOpts[job.Queue]; found && !job.MinResources.LessEqual(attr.deserved) {
+			return &api.ValidateResult{
+				Pass:   false,
+				Reason: v1alpha1.ExceedQueueResourcesReason,
+				Message: fmt.Sprintf("The minimal resources <%v> of job exceed the deserved resources <%v> of Queue <%s>",
+					job.MinResources, attr.deserved, attr.name),
 			}
-			totalWeight += attr.weight
 		}
 
-		// If no queues, break
-		if totalWeight == 0 {
-			break
+		return nil
+	})
+
+	ssn.AddJobEnqueueableFn(pp.Name(), func(obj interface{}) bool {
+		job := obj.(*api.JobInfo)
+		if job.MinResources == nil {
+			return true
 		}
 
-		// Calculates the deserved of each Queue.
-		deserved := api.EmptyResource()
-		for _, attr := range pp.queueOpts {
-			glog.V(4).Infof("Considering Queue <%s>: weight <%d>, total weight <%d>.",
-				attr.name, attr.weight, totalWeight)
-			if _, found := meet[attr.queueID]; found {
-				continue
+		// The job is admitted only if the idle deserved resources of its queue,
+		// and all of its ancestors, can hold its minimal resources.
+		for attr := pp.queueOpts[job.Queue]; attr != nil; attr = attr.parent {
+			used := attr.allocated.Clone().Add(attr.inqueue).Add(job.MinResources)
+			if !used.LessEqual(attr.deserved) {
+				glog.V(3).Infof("Queue <%v>: deserved <%v>, allocated <%v>, inqueue <%v>, can not hold <%v>",
+					attr.name, attr.deserved, attr.allocated, attr.inqueue, job.MinResources)
+				return false
 			}
+		}
 
-			oldDeserved := attr.deserved.Clone()
-			attr.deserved.Add(remaining.Clone().Multi(float64(attr.weight) / float64(totalWeight)))
-			if !attr.deserved.LessEqual(attr.request) {
-				attr.deserved = helpers.Min(attr.deserved, attr.request)
-				meet[attr.queueID] = struct{}{}
-			}
-			pp.updateShare(attr)
+		return true
+	})
 
-			glog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, allocate <%v>, request <%v>, share <%0.2f>",
-				attr.name, attr.deserved, attr.allocated, attr.request, attr.share)
+	ssn.AddJobEnqueuedFn(pp.Name(), func(obj interface{}) {
+		job := obj.(*api.JobInfo)
+		if job.MinResources == nil {
+			return
+		}
 
-			deserved.Add(attr.deserved.Clone().Sub(oldDeserved))
+		for attr := pp.queueOpts[job.Queue]; attr != nil; attr = attr.parent {
+			attr.inqueue.Add(job.MinResources)
 		}
+	})
 
-		remaining.Sub(deserved)
-		if remaining.IsEmpty() {
-			break
+	ssn.AddJobBackloggedFn(pp.Name(), func(obj interface{}) {
+		job := obj.(*api.JobInfo)
+
+		// The backlogged job is not scheduled in the session, so only its allocated
+		// resources are accounted, and the deserved resources are distributed again.
+		allocated, request, inqueue := jobResources(job)
+		for attr := pp.queueOpts[job.Queue]; attr != nil; attr = attr.parent {
+			attr.request.Sub(request).Add(allocated)
+			attr.inqueue.Sub(inqueue)
 		}
-	}
+		pp.distributeRoots()
+	})
 
 	ssn.AddQueueOrderFn(pp.Name(), func(l, r interface{}) int {
 		lv := l.(*api.QueueInfo)
 		rv := r.(*api.QueueInfo)
 
-		if pp.queueOpts[lv.UID].share == pp.queueOpts[rv.UID].share {
+		// Compare the queues by the share of their ancestors at the first
+		// level they diverge, so sibling sub-trees are balanced first.
+		lattr, rattr := pp.divergedAttrs(pp.queueOpts[lv.UID], pp.queueOpts[rv.UID])
+
+		if lattr.share == rattr.share {
 			return 0
 		}
 
-		if pp.queueOpts[lv.UID].share < pp.queueOpts[rv.UID].share {
+		if lattr.share < rattr.share {
 			return -1
 		}
 
@@ -161,22 +196,35 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		var victims []*api.TaskInfo
 		allocations := map[api.QueueID]*api.Resource{}
 
+		reclaimerAttr := pp.queueOpts[ssn.Jobs[reclaimer.Job].Queue]
+
 		for _, reclaimee := range reclaimees {
 			job := ssn.Jobs[reclaimee.Job]
 			attr := pp.queueOpts[job.Queue]
 
-			if _, found := allocations[job.Queue]; !found {
-				allocations[job.Queue] = attr.allocated.Clone()
-			}
-			allocated := allocations[job.Queue]
-			if allocated.Less(reclaimee.Resreq) {
-				glog.Errorf("Failed to allocate resource for Task <%s/%s> in Queue <%s>， not enough resource.",
-					reclaimee.Namespace, reclaimee.Name, job.Queue)
-				continue
+			// The reclaimee is a victim only if its queue, and all of its ancestors
+			// below the common ancestor with reclaimer, still get no less than
+			// deserved resources after reclaiming.
+			victim := true
+			for _, qa := range pp.reclaimLevels(reclaimerAttr, attr) {
+				if _, found := allocations[qa.queueID]; !found {
+					allocations[qa.queueID] = qa.allocated.Clone()
+				}
+				allocated := allocations[qa.queueID]
+				if allocated.Less(reclaimee.Resreq) {
+					glog.Errorf("Failed to allocate resource for Task <%s/%s> in Queue <%s>， not enough resource.",
+						reclaimee.Namespace, reclaimee.Name, qa.name)
+					victim = false
+					break
+				}
+
+				allocated.Sub(reclaimee.Resreq)
+				if !qa.deserved.LessEqual(allocated) {
+					victim = false
+				}
 			}
 
-			allocated.Sub(reclaimee.Resreq)
-			if attr.deserved.LessEqual(allocated) {
+			if victim {
 				victims = append(victims, reclaimee)
 			}
 		}
@@ -186,52 +234,316 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 
 	ssn.AddOverusedFn(pp.Name(), func(obj interface{}) bool {
 		queue := obj.(*api.QueueInfo)
-		attr := pp.queueOpts[queue.UID]
 
-		overused := attr.deserved.LessEqual(attr.allocated)
-		if overused {
-			glog.V(3).Infof("Queue <%v>: deserved <%v>, allocated <%v>, share <%v>",
-				queue.Name, attr.deserved, attr.allocated, attr.share)
+		// The queue is overused if any of its ancestors is overused.
+		for attr := pp.queueOpts[queue.UID]; attr != nil; attr = attr.parent {
+			if attr.deserved.LessEqual(attr.allocated) {
+				glog.V(3).Infof("Queue <%v>: deserved <%v>, allocated <%v>, share <%v>",
+					attr.name, attr.deserved, attr.allocated, attr.share)
+				return true
+			}
+
+			// Do not allocate more resources to the queue if it reached its capability.
+			if pp.reachCapability(attr) {
+				glog.V(3).Infof("Queue <%v>: capability <%v>, allocated <%v>",
+					attr.name, attr.capability, attr.allocated)
+				return true
+			}
+		}
+
+		return false
+	})
+
+	ssn.AddAllocatableFn(pp.Name(), func(queue *api.QueueInfo, candidate *api.TaskInfo) bool {
+		// The task is allocatable only if the allocated resources of its queue, and
+		// all of its ancestors, are still within their capability with the task.
+		for attr := pp.queueOpts[queue.UID]; attr != nil; attr = attr.parent {
+			if attr.capability == nil {
+				continue
+			}
+			allocated := attr.allocated.Clone().Add(candidate.Resreq)
+			if !allocated.LessEqual(attr.capability) {
+				glog.V(3).Infof("Queue <%v>: capability <%v>, allocated <%v>, can not allocate <%v> to Task <%v/%v>",
+					attr.name, attr.capability, attr.allocated, candidate.Resreq, candidate.Namespace, candidate.Name)
+				return false
+			}
 		}
 
-		return overused
+		return true
 	})
 
 	// Register event handlers.
 	ssn.AddEventHandler(&framework.EventHandler{
 		AllocateFunc: func(event *framework.Event) {
 			job := ssn.Jobs[event.Task.Job]
-			attr := pp.queueOpts[job.Queue]
-			attr.allocated.Add(event.Task.Resreq)
+			for attr := pp.queueOpts[job.Queue]; attr != nil; attr = attr.parent {
+				attr.allocated.Add(event.Task.Resreq)
 
-			pp.updateShare(attr)
+				pp.updateShare(attr)
 
-			glog.V(4).Infof("Proportion AllocateFunc: task <%v/%v>, resreq <%v>,  share <%v>",
-				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
+				glog.V(4).Infof("Proportion AllocateFunc: task <%v/%v>, resreq <%v>, queue <%v>, share <%v>",
+					event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.name, attr.share)
+			}
 		},
 		DeallocateFunc: func(event *framework.Event) {
 			job := ssn.Jobs[event.Task.Job]
-			attr := pp.queueOpts[job.Queue]
-			attr.allocated.Sub(event.Task.Resreq)
+			for attr := pp.queueOpts[job.Queue]; attr != nil; attr = attr.parent {
+				attr.allocated.Sub(event.Task.Resreq)
 
-			pp.updateShare(attr)
+				pp.updateShare(attr)
 
-			glog.V(4).Infof("Proportion EvictFunc: task <%v/%v>, resreq <%v>,  share <%v>",
-				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
+				glog.V(4).Infof("Proportion EvictFunc: task <%v/%v>, resreq <%v>, queue <%v>, share <%v>",
+					event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.name, attr.share)
+			}
 		},
 	})
 }
 
+// jobResources returns the allocated, requested and admitted but not allocated
+// resources of the job.
+func jobResources(job *api.JobInfo) (*api.Resource, *api.Resource, *api.Resource) {
+	allocated := api.EmptyResource()
+	request := api.EmptyResource()
+	for status, tasks := range job.TaskStatusIndex {
+		if api.AllocatedStatus(status) {
+			for _, t := range tasks {
+				allocated.Add(t.Resreq)
+				request.Add(t.Resreq)
+			}
+		} else if status == api.Pending {
+			for _, t := range tasks {
+				request.Add(t.Resreq)
+			}
+		}
+	}
+
+	// The job requests at least its minimal resources, even if its pods
+	// are not created yet.
+	if job.MinResources != nil {
+		request = helpers.Max(request, job.MinResources)
+	}
+
+	// The admitted job will use its minimal resources soon.
+	inqueue := api.EmptyResource()
+	if job.MinResources != nil && job.PodGroup != nil &&
+		job.PodGroup.Status.Phase == v1alpha1.PodGroupInqueue {
+		inqueue = helpers.Max(job.MinResources, allocated).Sub(allocated)
+	}
+
+	return allocated, request, inqueue
+}
+
+// distributeRoots divides the total resource among root queues, and then divides
+// the deserved resource of each queue among its children recursively.
+func (pp *proportionPlugin) distributeRoots() {
+	var roots []*queueAttr
+	for _, attr := range pp.queueOpts {
+		attr.deserved = api.EmptyResource()
+		if attr.parent == nil {
+			roots = append(roots, attr)
+		}
+	}
+	pp.distribute(pp.totalResource.Clone(), roots)
+}
+
+// buildQueueAttr builds the attributes of the queue and its ancestors if not
+// built yet. The queue is taken as a root queue if its parent is not found or
+// there's a cycle in its ancestors.
+func (pp *proportionPlugin) buildQueueAttr(ssn *framework.Session, queueID api.QueueID, visited map[api.QueueID]bool) *queueAttr {
+	if attr, found := pp.queueOpts[queueID]; found {
+		return attr
+	}
+
+	queue := ssn.Queues[queueID]
+	attr := &queueAttr{
+		queueID: queue.UID,
+		name:    queue.Name,
+		weight:  queue.Weight,
+
+		deserved:   api.EmptyResource(),
+		allocated:  api.EmptyResource(),
+		request:    api.EmptyResource(),
+		inqueue:    api.EmptyResource(),
+		capability: pp.queueCapability(queue),
+	}
+	visited[queueID] = true
+
+	if len(queue.Parent) != 0 {
+		if _, found := ssn.Queues[queue.Parent]; !found {
+			glog.Warningf("Failed to find parent Queue <%s> of Queue <%s>, take it as root Queue.",
+				queue.Parent, queue.Name)
+		} else if visited[queue.Parent] {
+			glog.Warningf("Found cycle in the ancestors of Queue <%s>, take it as root Queue.", queue.Name)
+		} else {
+			attr.parent = pp.buildQueueAttr(ssn, queue.Parent, visited)
+			attr.parent.children = append(attr.parent.children, attr)
+		}
+	}
+
+	pp.queueOpts[queueID] = attr
+	glog.V(4).Infof("Added Queue <%s> attributes.", queueID)
+
+	return attr
+}
+
+// distribute divides the remaining resource among sibling queues by their weights
+// until all of them meet their request or capability, so the resource not used by
+// a queue goes to its siblings first; and then divides the deserved resource of
+// each queue among its children.
+func (pp *proportionPlugin) distribute(remaining *api.Resource, queues []*queueAttr) {
+	meet := map[api.QueueID]struct{}{}
+	for {
+		totalWeight := int32(0)
+		for _, attr := range queues {
+			if _, found := meet[attr.queueID]; found {
+				continue
+			}
+			totalWeight += attr.weight
+		}
+
+		// If no queues, break
+		if totalWeight == 0 {
+			break
+		}
+
+		// Calculates the deserved of each Queue.
+		deserved := api.EmptyResource()
+		for _, attr := range queues {
+			glog.V(4).Infof("Considering Queue <%s>: weight <%d>, total weight <%d>.",
+				attr.name, attr.weight, totalWeight)
+			if _, found := meet[attr.queueID]; found {
+				continue
+			}
+
+			oldDeserved := attr.deserved.Clone()
+			attr.deserved.Add(remaining.Clone().Multi(float64(attr.weight) / float64(totalWeight)))
+			if !attr.deserved.LessEqual(attr.request) {
+				attr.deserved = helpers.Min(attr.deserved, attr.request)
+				meet[attr.queueID] = struct{}{}
+			}
+			if attr.capability != nil && !attr.deserved.LessEqual(attr.capability) {
+				attr.deserved = helpers.Min(attr.deserved, attr.capability)
+				meet[attr.queueID] = struct{}{}
+			}
+			pp.updateShare(attr)
+
+			glog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, allocate <%v>, request <%v>, share <%0.2f>",
+				attr.name, attr.deserved, attr.allocated, attr.request, attr.share)
+
+			deserved.Add(attr.deserved.Clone().Sub(oldDeserved))
+		}
+
+		remaining.Sub(deserved)
+		if remaining.IsEmpty() {
+			break
+		}
+	}
+
+	for _, attr := range queues {
+		if len(attr.children) != 0 {
+			pp.distribute(attr.deserved.Clone(), attr.children)
+		}
+	}
+}
+
+// ancestors returns the path from root queue to the given queue.
+func (pp *proportionPlugin) ancestors(attr *queueAttr) []*queueAttr {
+	var path []*queueAttr
+	for qa := attr; qa != nil; qa = qa.parent {
+		path = append([]*queueAttr{qa}, path...)
+	}
+	return path
+}
+
+// divergedAttrs returns the ancestors (or the queues themselves) of the two queues
+// at the first level their paths from root diverge.
+func (pp *proportionPlugin) divergedAttrs(l, r *queueAttr) (*queueAttr, *queueAttr) {
+	lpath, rpath := pp.ancestors(l), pp.ancestors(r)
+
+	i := 0
+	for i < len(lpath) && i < len(rpath) && lpath[i] == rpath[i] {
+		i++
+	}
+
+	if i < len(lpath) {
+		l = lpath[i]
+	}
+	if i < len(rpath) {
+		r = rpath[i]
+	}
+
+	return l, r
+}
+
+// reclaimLevels returns the queues whose resources are reclaimed when reclaiming
+// tasks in reclaimee's queue for reclaimer's queue: the reclaimee's queue and its
+// ancestors below their common ancestor.
+func (pp *proportionPlugin) reclaimLevels(reclaimer, reclaimee *queueAttr) []*queueAttr {
+	common := map[*queueAttr]bool{}
+	for qa := reclaimer; qa != nil; qa = qa.parent {
+		common[qa] = true
+	}
+
+	levels := []*queueAttr{reclaimee}
+	for qa := reclaimee.parent; qa != nil && !common[qa]; qa = qa.parent {
+		levels = append(levels, qa)
+	}
+
+	return levels
+}
+
 func (pp *proportionPlugin) OnSessionClose(ssn *framework.Session) {
 	pp.totalResource = nil
 	pp.queueOpts = nil
 }
 
+// queueCapability returns the upper limit of resources of the queue, the resources
+// not listed in the queue's capability are limited by the total resource of cluster.
+func (pp *proportionPlugin) queueCapability(queue *api.QueueInfo) *api.Resource {
+	if queue.Capability == nil {
+		return nil
+	}
+
+	capability := pp.totalResource.Clone()
+	for rn := range queue.Queue.Spec.Capability {
+		switch rn {
+		case v1.ResourceCPU:
+			capability.MilliCPU = queue.Capability.MilliCPU
+		case v1.ResourceMemory:
+			capability.Memory = queue.Capability.Memory
+		case v1.ResourcePods:
+		default:
+			capability.SetScalar(rn, queue.Capability.ScalarResources[rn])
+		}
+	}
+	// The GPU class is limited by the resources accounted as GPU.
+	capability.MilliGPU = capability.SumGPU()
+
+	return capability
+}
+
+// reachCapability checks whether the allocated resources of queue reached
+// its capability in any resource dimension.
+func (pp *proportionPlugin) reachCapability(attr *queueAttr) bool {
+	if attr.capability == nil {
+		return false
+	}
+
+	for _, rn := range attr.allocated.ResourceNames() {
+		allocated := attr.allocated.Get(rn)
+		if allocated > 0 && attr.capability.Get(rn) <= allocated {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (pp *proportionPlugin) updateShare(attr *queueAttr) {
 	res := float64(0)
 
 	// TODO(k82cn): how to handle fragment issues?
-	for _, rn := range api.ResourceNames() {
+	for _, rn := range attr.allocated.ResourceNames() {
 		share := helpers.Share(attr.allocated.Get(rn), attr.deserved.Get(rn))
 		if share > res {
 			res = share
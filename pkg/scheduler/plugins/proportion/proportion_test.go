@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+// buildQueueAttr builds the attributes of queue requesting the milli cpu; the request
+// is also accounted to its ancestors.
+func buildQueueAttr(name string, weight int32, request float64, parent *queueAttr) *queueAttr {
+	attr := &queueAttr{
+		queueID:   api.QueueID(name),
+		name:      name,
+		weight:    weight,
+		deserved:  api.EmptyResource(),
+		allocated: api.EmptyResource(),
+		request:   api.EmptyResource(),
+		inqueue:   api.EmptyResource(),
+		parent:    parent,
+	}
+	if parent != nil {
+		parent.children = append(parent.children, attr)
+	}
+	for qa := attr; qa != nil; qa = qa.parent {
+		qa.request.MilliCPU += request
+	}
+
+	return attr
+}
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() []*queueAttr
+		total    float64
+		expected map[string]float64
+	}{
+		{
+			name: "weights are split recursively",
+			build: func() []*queueAttr {
+				a := buildQueueAttr("a", 1, 0, nil)
+				b := buildQueueAttr("b", 3, 10000, nil)
+				buildQueueAttr("a1", 1, 10000, a)
+				buildQueueAttr("a2", 1, 10000, a)
+				return []*queueAttr{a, b}
+			},
+			total: 8000,
+			expected: map[string]float64{
+				"a":  2000,
+				"b":  6000,
+				"a1": 1000,
+				"a2": 1000,
+			},
+		},
+		{
+			name: "leftover share goes to siblings first",
+			build: func() []*queueAttr {
+				a := buildQueueAttr("a", 1, 0, nil)
+				b := buildQueueAttr("b", 1, 10000, nil)
+				buildQueueAttr("a1", 1, 1000, a)
+				buildQueueAttr("a2", 1, 10000, a)
+				return []*queueAttr{a, b}
+			},
+			total: 8000,
+			expected: map[string]float64{
+				"a":  4000,
+				"b":  4000,
+				"a1": 1000,
+				"a2": 3000,
+			},
+		},
+		{
+			name: "leftover share of sub-tree goes to other sub-trees",
+			build: func() []*queueAttr {
+				a := buildQueueAttr("a", 1, 0, nil)
+				b := buildQueueAttr("b", 1, 10000, nil)
+				buildQueueAttr("a1", 1, 1000, a)
+				buildQueueAttr("a2", 1, 1000, a)
+				return []*queueAttr{a, b}
+			},
+			total: 8000,
+			expected: map[string]float64{
+				"a":  2000,
+				"b":  6000,
+				"a1": 1000,
+				"a2": 1000,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		pp := &proportionPlugin{}
+		roots := test.build()
+		pp.distribute(&api.Resource{MilliCPU: test.total}, roots)
+
+		deserved := map[string]float64{}
+		var collect func(attrs []*queueAttr)
+		collect = func(attrs []*queueAttr) {
+			for _, attr := range attrs {
+				deserved[attr.name] = attr.deserved.MilliCPU
+				collect(attr.children)
+			}
+		}
+		collect(roots)
+
+		for name, expected := range test.expected {
+			if deserved[name] != expected {
+				t.Errorf("case %s: expected deserved milli cpu %v of queue %s, got %v",
+					test.name, expected, name, deserved[name])
+			}
+		}
+	}
+}
+
+func TestQueueCapabilityWithGPUClass(t *testing.T) {
+	const amdGPUResourceName = "amd.com/gpu"
+
+	ra := api.NewResourceAccounting()
+	ra.GPUResourceNames[amdGPUResourceName] = true
+	api.SetResourceAccounting(ra)
+	defer api.SetResourceAccounting(api.NewResourceAccounting())
+
+	pp := &proportionPlugin{
+		totalResource: api.NewResource(v1.ResourceList{
+			v1.ResourceCPU:      resource.MustParse("8"),
+			api.GPUResourceName: resource.MustParse("4"),
+			amdGPUResourceName:  resource.MustParse("4"),
+		}),
+	}
+
+	queue := api.NewQueueInfo(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "q1"},
+		Spec: kbv1.QueueSpec{
+			Weight: 1,
+			Capability: v1.ResourceList{
+				amdGPUResourceName: resource.MustParse("1"),
+			},
+		},
+	})
+
+	capability := pp.queueCapability(queue)
+	if amd := capability.ScalarResources[amdGPUResourceName]; amd != 1000 {
+		t.Errorf("expected capability of 1000 milli %s, got %v", amdGPUResourceName, amd)
+	}
+	if nvidia := capability.ScalarResources[api.GPUResourceName]; nvidia != 4000 {
+		t.Errorf("expected capability of 4000 milli %s, got %v", api.GPUResourceName, nvidia)
+	}
+	if capability.MilliGPU != 5000 {
+		t.Errorf("expected capability of 5000 milli GPU, got %v", capability.MilliGPU)
+	}
+}
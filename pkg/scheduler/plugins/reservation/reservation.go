@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+)
+
+type reservationPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments map[string]string
+}
+
+// New returns a plugin which lets the owners of reservations use the reserved resources first.
+func New(arguments map[string]string) framework.Plugin {
+	return &reservationPlugin{pluginArguments: arguments}
+}
+
+func (rp *reservationPlugin) Name() string {
+	return "reservation"
+}
+
+func (rp *reservationPlugin) OnSessionOpen(ssn *framework.Session) {
+	now := time.Now()
+	owners := map[api.JobID]bool{}
+	for _, r := range ssn.Reservations {
+		if !r.Expired(now) {
+			owners[r.Owner] = true
+		}
+	}
+
+	jobOrderFn := func(l, r interface{}) int {
+		lv := l.(*api.JobInfo)
+		rv := r.(*api.JobInfo)
+
+		glog.V(4).Infof("Reservation JobOrderFn: <%v/%v> owner: %t, <%v/%v> owner: %t",
+			lv.Namespace, lv.Name, owners[lv.UID], rv.Namespace, rv.Name, owners[rv.UID])
+
+		if owners[lv.UID] == owners[rv.UID] {
+			return 0
+		}
+
+		if owners[lv.UID] {
+			return -1
+		}
+
+		return 1
+	}
+
+	ssn.AddJobOrderFn(rp.Name(), jobOrderFn)
+
+	// The reserved resources are only changed by allocation, so it's safe to
+	// call it in parallel.
+	nodeMapFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		if reserved := ssn.Reserved(task.Job, node.Name); reserved == nil || reserved.IsEmpty() {
+			return 0, nil
+		}
+
+		glog.V(4).Infof("Reservation NodeMapFn: resources are reserved on <%v> for Task <%v/%v>",
+			node.Name, task.Namespace, task.Name)
+
+		return api.MaxNodeScore, nil
+	}
+
+	ssn.AddNodeMapFn(rp.Name(), nodeMapFn)
+}
+
+func (rp *reservationPlugin) OnSessionClose(ssn *framework.Session) {}
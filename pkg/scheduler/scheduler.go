@@ -17,26 +17,59 @@ limitations under the License.
 package scheduler
 
 import (
-	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
 
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 	schedcache "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/cache"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/metrics"
 )
 
+const (
+	// schedulerConfReloadPeriod is the period to check whether the configuration file is changed.
+	schedulerConfReloadPeriod = 10 * time.Second
+
+	// serviceAccountNamespace is the file of the namespace of scheduler pod.
+	serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
 type Scheduler struct {
 	cache          schedcache.Cache
 	config         *rest.Config
 	actions        []framework.Action
 	plugins        []conf.Tier
+	configurations []conf.Configuration
 	schedulerConf  string
 	schedulePeriod time.Duration
+
+	// mutex protects actions, plugins and configurations, which are swapped
+	// between sessions once the configuration file is changed.
+	mutex sync.Mutex
+	// loadedConf is the content of configuration file loaded last time.
+	loadedConf string
+	// accounting is the resource accounting in use, which is not changed by reload.
+	accounting *api.ResourceAccounting
+
+	recorder record.EventRecorder
+	ref      *v1.ObjectReference
 }
 
 func NewScheduler(
@@ -45,40 +78,133 @@ func NewScheduler(
 	conf string,
 	period time.Duration,
 	defaultQueue string,
+	bindOpts schedcache.BindOptions,
+	evictOpts schedcache.EvictOptions,
 ) (*Scheduler, error) {
 	scheduler := &Scheduler{
 		config:         config,
 		schedulerConf:  conf,
-		cache:          schedcache.New(config, schedulerName, defaultQueue),
 		schedulePeriod: period,
 	}
 
+	// Load configuration of scheduler
+	if err := scheduler.loadSchedulerConf(); err != nil {
+		return nil, err
+	}
+
+	scheduler.cache = schedcache.New(config, schedulerName, defaultQueue, bindOpts, evictOpts)
+
+	// Prepare event clients.
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{
+		Interface: kubernetes.NewForConfigOrDie(config).CoreV1().Events(""),
+	})
+	scheduler.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "kube-batch"})
+	scheduler.ref = schedulerReference()
+
 	return scheduler, nil
 }
 
 func (pc *Scheduler) Run(stopCh <-chan struct{}) {
-	var err error
+	// The resources are accounted when they're added to cache, so
+	// it's set before starting cache.
+	api.SetResourceAccounting(pc.accounting)
 
 	// Start cache for policy.
 	go pc.cache.Run(stopCh)
 	pc.cache.WaitForCacheSync(stopCh)
 
-	// Load configuration of scheduler
+	if len(pc.schedulerConf) != 0 {
+		go wait.Until(pc.reloadSchedulerConf, schedulerConfReloadPeriod, stopCh)
+	}
+	go wait.Until(pc.runOnce, pc.schedulePeriod, stopCh)
+}
+
+// loadSchedulerConf loads the configuration file at startup, or the default configuration
+// if no file is given; an error is returned if the file can not be read or is invalid.
+func (pc *Scheduler) loadSchedulerConf() error {
+	var err error
+
 	schedConf := defaultSchedulerConf
 	if len(pc.schedulerConf) != 0 {
 		if schedConf, err = readSchedulerConf(pc.schedulerConf); err != nil {
-			glog.Errorf("Failed to read scheduler configuration '%s', using default configuration: %v",
-				pc.schedulerConf, err)
-			schedConf = defaultSchedulerConf
+			metrics.RegisterSchedulerConfLoad(metrics.SchedulerConfLoadError)
+			return fmt.Errorf("failed to read scheduler configuration '%s': %v", pc.schedulerConf, err)
 		}
 	}
 
-	pc.actions, pc.plugins, err = loadSchedulerConf(schedConf)
+	pc.actions, pc.plugins, pc.configurations, pc.accounting, err = loadSchedulerConf(schedConf)
 	if err != nil {
-		panic(err)
+		metrics.RegisterSchedulerConfLoad(metrics.SchedulerConfLoadError)
+		return fmt.Errorf("invalid scheduler configuration '%s': %v", pc.schedulerConf, err)
 	}
+	pc.loadedConf = schedConf
 
-	go wait.Until(pc.runOnce, pc.schedulePeriod, stopCh)
+	metrics.RegisterSchedulerConfLoad(metrics.SchedulerConfLoadSuccess)
+	return nil
+}
+
+// reloadSchedulerConf loads the configuration file if it's changed, and swaps the actions
+// and plugins for the next session; the invalid configuration is rejected, and the resources
+// section is only loaded at startup, as the resources in cache are accounted by it.
+func (pc *Scheduler) reloadSchedulerConf() {
+	schedConf, err := readSchedulerConf(pc.schedulerConf)
+	if err != nil {
+		glog.Errorf("Failed to read scheduler configuration '%s': %v", pc.schedulerConf, err)
+		return
+	}
+	if schedConf == pc.loadedConf {
+		return
+	}
+	pc.loadedConf = schedConf
+
+	actions, plugins, configurations, accounting, err := loadSchedulerConf(schedConf)
+	if err != nil {
+		pc.rejectSchedulerConf(err)
+		return
+	}
+
+	if !reflect.DeepEqual(accounting, pc.accounting) {
+		glog.Warningf("The resources section of scheduler configuration '%s' is changed, it takes effect after restart.",
+			pc.schedulerConf)
+		pc.recorder.Eventf(pc.ref, v1.EventTypeWarning, "SchedulerConfResourcesIgnored",
+			"The resources section of scheduler configuration %s takes effect after restart", pc.schedulerConf)
+	}
+
+	pc.mutex.Lock()
+	pc.actions, pc.plugins, pc.configurations = actions, plugins, configurations
+	pc.mutex.Unlock()
+
+	glog.V(3).Infof("Reloaded scheduler configuration '%s'.", pc.schedulerConf)
+	metrics.RegisterSchedulerConfLoad(metrics.SchedulerConfLoadSuccess)
+	pc.recorder.Eventf(pc.ref, v1.EventTypeNormal, "SchedulerConfReloaded",
+		"Reloaded scheduler configuration %s", pc.schedulerConf)
+}
+
+// rejectSchedulerConf reports the invalid configuration.
+func (pc *Scheduler) rejectSchedulerConf(err error) {
+	glog.Errorf("Rejected invalid scheduler configuration '%s': %v", pc.schedulerConf, err)
+	metrics.RegisterSchedulerConfLoad(metrics.SchedulerConfLoadError)
+	pc.recorder.Eventf(pc.ref, v1.EventTypeWarning, "InvalidSchedulerConf",
+		"Rejected invalid scheduler configuration %s: %v", pc.schedulerConf, err)
+}
+
+// schedulerReference returns the reference of scheduler pod for events; the name of pod
+// is its hostname, and its namespace is the one of its service account.
+func schedulerReference() *v1.ObjectReference {
+	ref := &v1.ObjectReference{
+		Kind:      "Pod",
+		Namespace: metav1.NamespaceDefault,
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		ref.Name = hostname
+	}
+	if namespace, err := ioutil.ReadFile(serviceAccountNamespace); err == nil {
+		ref.Namespace = strings.TrimSpace(string(namespace))
+	}
+
+	return ref
 }
 
 func (pc *Scheduler) runOnce() {
@@ -87,10 +213,15 @@ func (pc *Scheduler) runOnce() {
 	defer glog.V(4).Infof("End scheduling ...")
 	defer metrics.UpdateE2eDuration(metrics.Duration(scheduleStartTime))
 
-	ssn := framework.OpenSession(pc.cache, pc.plugins)
+	// The configuration is not changed within a session.
+	pc.mutex.Lock()
+	actions, plugins, configurations := pc.actions, pc.plugins, pc.configurations
+	pc.mutex.Unlock()
+
+	ssn := framework.OpenSession(pc.cache, plugins, configurations)
 	defer framework.CloseSession(ssn)
 
-	for _, action := range pc.actions {
+	for _, action := range actions {
 		actionStartTime := time.Now()
 		action.Execute(ssn)
 		metrics.UpdateActionDuration(action.Name(), metrics.Duration(actionStartTime))
@@ -0,0 +1,250 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/metrics"
+
+	_ "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions"
+	_ "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/plugins"
+)
+
+// schedulerConfLoads returns the count of scheduler_conf_loads_total by the result.
+func schedulerConfLoads(t *testing.T, result string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != metrics.KubeBatchNamespace+"_scheduler_conf_loads_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" && label.GetValue() == result {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func actionNames(pc *Scheduler) []string {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	var names []string
+	for _, action := range pc.actions {
+		names = append(names, action.Name())
+	}
+	return names
+}
+
+func TestReloadSchedulerConf(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-batch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	confPath := filepath.Join(dir, "kube-batch-conf.yaml")
+	writeConf := func(schedConf string) {
+		if err := ioutil.WriteFile(confPath, []byte(schedConf), 0644); err != nil {
+			t.Fatalf("failed to write scheduler configuration: %v", err)
+		}
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	pc := &Scheduler{
+		schedulerConf: confPath,
+		recorder:      recorder,
+		ref:           &v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "kube-batch"},
+	}
+
+	tests := []struct {
+		name      string
+		schedConf string
+		actions   []string
+		event     string
+		result    string
+	}{
+		{
+			name: "changed configuration is swapped",
+			schedConf: `
+actions: "enqueue, allocate, backfill"
+tiers:
+- plugins:
+  - name: gang
+`,
+			actions: []string{"enqueue", "allocate", "backfill"},
+			event:   "SchedulerConfReloaded",
+			result:  metrics.SchedulerConfLoadSuccess,
+		},
+		{
+			name: "invalid configuration is rejected and the previous one is kept",
+			schedConf: `
+actions: "enqueue, allocate, unknown"
+tiers:
+- plugins:
+  - name: gang
+`,
+			actions: []string{"enqueue", "allocate", "backfill"},
+			event:   "InvalidSchedulerConf",
+			result:  metrics.SchedulerConfLoadError,
+		},
+		{
+			name: "changed resources are ignored",
+			schedConf: `
+actions: "enqueue, allocate, preempt"
+tiers:
+- plugins:
+  - name: gang
+resources:
+  classes:
+    example.com/gpu: gpu
+`,
+			actions: []string{"enqueue", "allocate", "preempt"},
+			event:   "SchedulerConfResourcesIgnored",
+			result:  metrics.SchedulerConfLoadSuccess,
+		},
+	}
+
+	writeConf(`
+actions: "enqueue, allocate"
+tiers:
+- plugins:
+  - name: gang
+`)
+	loads := schedulerConfLoads(t, metrics.SchedulerConfLoadSuccess)
+	if err := pc.loadSchedulerConf(); err != nil {
+		t.Fatalf("failed to load scheduler configuration at startup: %v", err)
+	}
+	if names := actionNames(pc); !reflect.DeepEqual(names, []string{"enqueue", "allocate"}) {
+		t.Fatalf("expected actions loaded at startup, got %v", names)
+	}
+	if count := schedulerConfLoads(t, metrics.SchedulerConfLoadSuccess); count != loads+1 {
+		t.Errorf("expected the load at startup to be counted, got %v loads", count-loads)
+	}
+	accounting := pc.accounting
+
+	for _, test := range tests {
+		loads := schedulerConfLoads(t, test.result)
+
+		writeConf(test.schedConf)
+		pc.reloadSchedulerConf()
+
+		if names := actionNames(pc); !reflect.DeepEqual(names, test.actions) {
+			t.Errorf("case %s: expected actions %v, got %v", test.name, test.actions, names)
+		}
+		if count := schedulerConfLoads(t, test.result); count != loads+1 {
+			t.Errorf("case %s: expected one %s load to be counted, got %v", test.name, test.result, count-loads)
+		}
+		if pc.accounting != accounting {
+			t.Errorf("case %s: expected resource accounting not to be changed by reload", test.name)
+		}
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		found := false
+		for _, event := range events {
+			if strings.Contains(event, test.event) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("case %s: expected event %s, got %v", test.name, test.event, events)
+		}
+	}
+
+	// The unchanged configuration is not reloaded.
+	pc.reloadSchedulerConf()
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected no event for unchanged configuration, got %v", <-recorder.Events)
+	}
+
+	if accounting.GPUResourceNames["example.com/gpu"] {
+		t.Errorf("expected the resources section not to be loaded by reload")
+	}
+}
+
+func TestLoadSchedulerConfAtStartup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-batch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	invalidConf := filepath.Join(dir, "invalid-conf.yaml")
+	if err := ioutil.WriteFile(invalidConf, []byte(`actions: "enqueue, allocate, unknown"`), 0644); err != nil {
+		t.Fatalf("failed to write scheduler configuration: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		schedulerConf string
+		valid         bool
+	}{
+		{
+			name:  "default configuration is loaded without file",
+			valid: true,
+		},
+		{
+			name:          "invalid configuration fails the startup",
+			schedulerConf: invalidConf,
+		},
+		{
+			name:          "unreadable configuration fails the startup",
+			schedulerConf: filepath.Join(dir, "missing-conf.yaml"),
+		},
+	}
+
+	for _, test := range tests {
+		result := metrics.SchedulerConfLoadError
+		if test.valid {
+			result = metrics.SchedulerConfLoadSuccess
+		}
+		loads := schedulerConfLoads(t, result)
+
+		pc := &Scheduler{schedulerConf: test.schedulerConf}
+		err := pc.loadSchedulerConf()
+		if test.valid != (err == nil) {
+			t.Errorf("case %s: expected valid %t, got error %v", test.name, test.valid, err)
+		}
+		if !test.valid && len(actionNames(pc)) != 0 {
+			t.Errorf("case %s: expected no fallback to default configuration, got actions %v",
+				test.name, actionNames(pc))
+		}
+		if count := schedulerConfLoads(t, result); count != loads+1 {
+			t.Errorf("case %s: expected one %s load to be counted, got %v", test.name, result, count-loads)
+		}
+	}
+}
@@ -23,12 +23,16 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/conf"
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
 )
 
 var defaultSchedulerConf = `
-actions: "allocate, backfill"
+actions: "enqueue, allocate, backfill"
 tiers:
 - plugins:
   - name: priority
@@ -40,7 +44,7 @@ tiers:
   - name: nodeorder
 `
 
-func loadSchedulerConf(confStr string) ([]framework.Action, []conf.Tier, error) {
+func loadSchedulerConf(confStr string) ([]framework.Action, []conf.Tier, []conf.Configuration, *api.ResourceAccounting, error) {
 	var actions []framework.Action
 
 	schedulerConf := &conf.SchedulerConfiguration{}
@@ -48,19 +52,91 @@ func loadSchedulerConf(confStr string) ([]framework.Action, []conf.Tier, error)
 	buf := make([]byte, len(confStr))
 	copy(buf, confStr)
 
-	if err := yaml.Unmarshal(buf, schedulerConf); err != nil {
-		return nil, nil, err
+	// Reject the unknown fields, e.g. the misspelt options of plugins.
+	if err := yaml.UnmarshalStrict(buf, schedulerConf); err != nil {
+		return nil, nil, nil, nil, err
 	}
 	actionNames := strings.Split(schedulerConf.Actions, ",")
+	for i := range actionNames {
+		actionNames[i] = strings.TrimSpace(actionNames[i])
+	}
+
+	if err := framework.ValidateSchedulerConf(actionNames, schedulerConf.Tiers, schedulerConf.Configurations); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	for _, actionName := range actionNames {
-		if action, found := framework.GetAction(strings.TrimSpace(actionName)); found {
+		if action, found := framework.GetAction(actionName); found {
 			actions = append(actions, action)
 		} else {
-			return nil, nil, fmt.Errorf("failed to found Action %s, ignore it", actionName)
+			return nil, nil, nil, nil, fmt.Errorf("failed to found Action %s, ignore it", actionName)
 		}
 	}
 
-	return actions, schedulerConf.Tiers, nil
+	accounting, err := newResourceAccounting(schedulerConf.Resources)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return actions, schedulerConf.Tiers, schedulerConf.Configurations, accounting, nil
+}
+
+// newResourceAccounting builds ResourceAccounting from the resources section of configuration.
+func newResourceAccounting(rc conf.ResourceConfiguration) (*api.ResourceAccounting, error) {
+	ra := api.NewResourceAccounting()
+
+	for name, class := range rc.Classes {
+		rn := v1.ResourceName(strings.TrimSpace(name))
+		if rn == v1.ResourceCPU || rn == v1.ResourceMemory || rn == v1.ResourcePods {
+			return nil, fmt.Errorf("the class of resource %s can not be changed", rn)
+		}
+
+		switch strings.TrimSpace(class) {
+		case conf.GPUResourceClass:
+			ra.GPUResourceNames[rn] = true
+		case conf.ScalarResourceClass:
+			delete(ra.GPUResourceNames, rn)
+		default:
+			return nil, fmt.Errorf("unknown class %s of resource %s", class, rn)
+		}
+	}
+
+	for name, delta := range rc.MinDeltas {
+		rn := v1.ResourceName(strings.TrimSpace(name))
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(delta))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse minimal delta of resource %s: %v", rn, err)
+		}
+
+		switch {
+		case rn == v1.ResourceCPU:
+			ra.MinMilliCPU = float64(quantity.MilliValue())
+		case rn == v1.ResourceMemory:
+			ra.MinMemory = float64(quantity.Value())
+		case rn == v1.ResourcePods:
+			return nil, fmt.Errorf("minimal delta of resource %s is not supported", rn)
+		case ra.GPUResourceNames[rn]:
+			ra.MinMilliGPU = float64(quantity.MilliValue())
+		default:
+			ra.MinMilliScalars[rn] = float64(quantity.MilliValue())
+		}
+	}
+
+	return ra, nil
+}
+
+// ValidateSchedulerConf validates the scheduler configuration file, e.g. for the
+// configuration to be deployed; the error explains why the configuration is invalid.
+func ValidateSchedulerConf(confPath string) error {
+	schedConf, err := readSchedulerConf(confPath)
+	if err != nil {
+		return err
+	}
+
+	if _, _, _, _, err := loadSchedulerConf(schedConf); err != nil {
+		return fmt.Errorf("invalid scheduler configuration %s: %v", confPath, err)
+	}
+	return nil
 }
 
 func readSchedulerConf(confPath string) (string, error) {
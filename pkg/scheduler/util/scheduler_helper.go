@@ -0,0 +1,224 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golang/glog"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+// parallelism is the number of workers evaluating predicates and scores of nodes.
+const parallelism = 16
+
+// GetNodeList returns the nodes sorted by name, so the nodes are evaluated in
+// the same order by every session.
+func GetNodeList(nodes map[string]*api.NodeInfo) []*api.NodeInfo {
+	result := make([]*api.NodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, node)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+const (
+	// NodesToFindArg is the argument of actions to stop searching the feasible nodes
+	// for a task after so many nodes are found.
+	NodesToFindArg = "nodesToFind"
+	// PercentageOfNodesToFindArg is the argument of actions to stop searching the
+	// feasible nodes for a task after the percentage of nodes in cluster are found.
+	PercentageOfNodesToFindArg = "percentageOfNodesToFind"
+)
+
+// NodeSampler searches the feasible nodes for tasks; it stops searching after
+// enough nodes are found, and the next search starts from the node after the last
+// one found, so the tasks are spread across nodes. It trades a little placement
+// quality for lower latency of the tasks in large cluster.
+type NodeSampler struct {
+	// NodesToFind is the number of feasible nodes to find, 0 for no limit.
+	NodesToFind int
+	// PercentageOfNodesToFind is the percentage of nodes to find, 0 for no limit.
+	// If both limits are set, the larger number of nodes is found.
+	PercentageOfNodesToFind int
+
+	// start is the index of node to start next search from.
+	start int
+}
+
+// SetLimits sets the limits of the feasible nodes to find; the invalid limits are
+// corrected with a warning.
+func (ns *NodeSampler) SetLimits(nodesToFind, percentageOfNodesToFind int) {
+	if nodesToFind < 0 {
+		glog.Warningf("Invalid %s %d, search all nodes.", NodesToFindArg, nodesToFind)
+		nodesToFind = 0
+	}
+	if percentageOfNodesToFind < 0 || percentageOfNodesToFind > 100 {
+		glog.Warningf("Invalid %s %d, search all nodes.", PercentageOfNodesToFindArg, percentageOfNodesToFind)
+		percentageOfNodesToFind = 0
+	}
+
+	ns.NodesToFind = nodesToFind
+	ns.PercentageOfNodesToFind = percentageOfNodesToFind
+}
+
+// NumNodesToFind returns the number of feasible nodes to find in the given number of nodes.
+func (ns *NodeSampler) NumNodesToFind(numAllNodes int) int {
+	if ns.NodesToFind <= 0 && ns.PercentageOfNodesToFind <= 0 {
+		return numAllNodes
+	}
+
+	numNodes := ns.NodesToFind
+	if ns.PercentageOfNodesToFind > 0 {
+		numByPercentage := numAllNodes * ns.PercentageOfNodesToFind / 100
+		if numByPercentage < 1 {
+			numByPercentage = 1
+		}
+		if numByPercentage > numNodes {
+			numNodes = numByPercentage
+		}
+	}
+
+	if numNodes > numAllNodes {
+		return numAllNodes
+	}
+	return numNodes
+}
+
+// PredicateNodes returns the nodes fit for the task, up to the limits of sampler;
+// the nodes are searched in the order of the given nodes, starting from the node
+// after the last one found by previous search. The nodes are predicated in parallel
+// by chunks, so fn must be goroutine-safe; the search stops after the chunk in which
+// enough nodes are found, and every chunk is predicated completely, so the nodes found
+// and the start of next search are the same as predicating the nodes one by one.
+func (ns *NodeSampler) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeInfo, fn api.PredicateFn) []*api.NodeInfo {
+	numAllNodes := len(nodes)
+	if numAllNodes == 0 {
+		return nil
+	}
+
+	numNodesToFind := ns.NumNodesToFind(numAllNodes)
+	start := ns.start % numAllNodes
+	fit := make([]bool, numAllNodes)
+
+	checkNode := func(index int) {
+		node := nodes[(start+index)%numAllNodes]
+		glog.V(3).Infof("Considering Task <%v/%v> on node <%v>: <%v> vs. <%v>",
+			task.Namespace, task.Name, node.Name, task.Resreq, node.Idle)
+
+		if err := fn(task, node); err != nil {
+			glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
+				task.Namespace, task.Name, node.Name, err)
+			return
+		}
+		fit[index] = true
+	}
+
+	var predicateNodes []*api.NodeInfo
+	processed := numAllNodes
+	for checked := 0; checked < numAllNodes && len(predicateNodes) < numNodesToFind; {
+		// Predicate at least as many nodes as still to find in a chunk.
+		chunk := numNodesToFind - len(predicateNodes)
+		if chunk < parallelism {
+			chunk = parallelism
+		}
+		if chunk > numAllNodes-checked {
+			chunk = numAllNodes - checked
+		}
+
+		offset := checked
+		workqueue.ParallelizeUntil(context.TODO(), parallelism, chunk, func(index int) {
+			checkNode(offset + index)
+		})
+
+		for i := offset; i < offset+chunk && len(predicateNodes) < numNodesToFind; i++ {
+			if fit[i] {
+				predicateNodes = append(predicateNodes, nodes[(start+i)%numAllNodes])
+				processed = i + 1
+			}
+		}
+		checked += chunk
+	}
+	if len(predicateNodes) < numNodesToFind {
+		processed = numAllNodes
+	}
+	ns.start = (start + processed) % numAllNodes
+
+	glog.V(4).Infof("Found <%d> feasible nodes of <%d> nodes for Task <%v/%v>",
+		len(predicateNodes), numAllNodes, task.Namespace, task.Name)
+
+	return predicateNodes
+}
+
+// PredicateNodes returns all nodes fit for the task, in the order of the given nodes;
+// the predicates are evaluated in parallel, so fn must be goroutine-safe.
+func PredicateNodes(task *api.TaskInfo, nodes []*api.NodeInfo, fn api.PredicateFn) []*api.NodeInfo {
+	return (&NodeSampler{}).PredicateNodes(task, nodes, fn)
+}
+
+// PrioritizeNodes returns the nodes indexed by their total scores for the task; the nodes
+// of the same score are in the order of the given nodes. The nodes are scored by mapFn in
+// parallel, so mapFn must be goroutine-safe; then the scores of all nodes are reduced to
+// the total scores by reduceFn.
+func PrioritizeNodes(task *api.TaskInfo, nodes []*api.NodeInfo,
+	mapFn api.NodeOrderMapFn, reduceFn api.NodeOrderReduceFn) map[float64][]*api.NodeInfo {
+	scores := make([]map[string]float64, len(nodes))
+	errs := make([]error, len(nodes))
+
+	scoreNode := func(index int) {
+		scores[index], errs[index] = mapFn(task, nodes[index])
+	}
+	workqueue.ParallelizeUntil(context.TODO(), parallelism, len(nodes), scoreNode)
+
+	var scoredNodes []*api.NodeInfo
+	pluginScores := map[string]map[string]float64{}
+	for i, node := range nodes {
+		if errs[i] != nil {
+			glog.V(3).Infof("Error in Calculating Priority for the node:%v", errs[i])
+			continue
+		}
+		scoredNodes = append(scoredNodes, node)
+		for plugin, score := range scores[i] {
+			if _, found := pluginScores[plugin]; !found {
+				pluginScores[plugin] = map[string]float64{}
+			}
+			pluginScores[plugin][node.Name] = score
+		}
+	}
+
+	nodeScores := map[float64][]*api.NodeInfo{}
+	totals, err := reduceFn(task, scoredNodes, pluginScores)
+	if err != nil {
+		glog.V(3).Infof("Error in Reducing Priority for Task <%v/%v>: %v", task.Namespace, task.Name, err)
+		nodeScores[0] = scoredNodes
+		return nodeScores
+	}
+
+	for _, node := range scoredNodes {
+		nodeScores[totals[node.Name]] = append(nodeScores[totals[node.Name]], node)
+	}
+
+	return nodeScores
+}
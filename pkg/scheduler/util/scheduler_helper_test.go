@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+func TestPredicateAndPrioritizeNodes(t *testing.T) {
+	nodes := map[string]*api.NodeInfo{}
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("n%03d", i)
+		nodes[name] = &api.NodeInfo{Name: name}
+	}
+	task := &api.TaskInfo{Namespace: "c1", Name: "p1"}
+
+	// Nodes with odd index fit the task, and are scored by index % 3.
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		var i int
+		fmt.Sscanf(node.Name, "n%03d", &i)
+		if i%2 == 0 {
+			return fmt.Errorf("node %s is even", node.Name)
+		}
+		return nil
+	}
+	mapFn := func(task *api.TaskInfo, node *api.NodeInfo) (map[string]float64, error) {
+		var i int
+		fmt.Sscanf(node.Name, "n%03d", &i)
+		return map[string]float64{"p1": float64(i % 3)}, nil
+	}
+	reduceFn := func(task *api.TaskInfo, nodes []*api.NodeInfo, scores map[string]map[string]float64) (map[string]float64, error) {
+		return scores["p1"], nil
+	}
+
+	var expected []string
+	for score := 2; score >= 0; score-- {
+		for i := 1; i < 100; i += 2 {
+			if i%3 == score {
+				expected = append(expected, fmt.Sprintf("n%03d", i))
+			}
+		}
+	}
+
+	for round := 0; round < 10; round++ {
+		predicateNodes := PredicateNodes(task, GetNodeList(nodes), predicateFn)
+		selected := SelectBestNode(PrioritizeNodes(task, predicateNodes, mapFn, reduceFn))
+
+		var got []string
+		for _, node := range selected {
+			got = append(got, node.Name)
+		}
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("round %d: expected nodes %v, got %v", round, expected, got)
+		}
+	}
+}
+
+func TestNodeSampler(t *testing.T) {
+	nodes := map[string]*api.NodeInfo{}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("n%d", i)
+		nodes[name] = &api.NodeInfo{Name: name}
+	}
+	task := &api.TaskInfo{Namespace: "c1", Name: "p1"}
+
+	// All nodes but n3 fit the task.
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		if node.Name == "n3" {
+			return fmt.Errorf("node %s is full", node.Name)
+		}
+		return nil
+	}
+
+	sampler := &NodeSampler{}
+	sampler.SetLimits(2, 10)
+
+	expected := [][]string{
+		{"n0", "n1"},
+		{"n2", "n4"},
+		{"n5", "n6"},
+		{"n7", "n8"},
+		{"n9", "n0"},
+	}
+	for round, names := range expected {
+		var got []string
+		for _, node := range sampler.PredicateNodes(task, GetNodeList(nodes), predicateFn) {
+			got = append(got, node.Name)
+		}
+		if !reflect.DeepEqual(names, got) {
+			t.Errorf("round %d: expected nodes %v, got %v", round, names, got)
+		}
+	}
+
+	for _, test := range []struct {
+		nodesToFind             int
+		percentageOfNodesToFind int
+		numAllNodes             int
+		expected                int
+	}{
+		{0, 0, 1000, 1000},
+		{100, 0, 1000, 100},
+		{100, 0, 10, 10},
+		{0, 5, 1000, 50},
+		{0, 5, 10, 1},
+		{100, 5, 1000, 100},
+		{100, 50, 1000, 500},
+		{-1, 101, 1000, 1000},
+	} {
+		sampler := &NodeSampler{}
+		sampler.SetLimits(test.nodesToFind, test.percentageOfNodesToFind)
+		if got := sampler.NumNodesToFind(test.numAllNodes); got != test.expected {
+			t.Errorf("nodesToFind %d, percentageOfNodesToFind %d of %d nodes: expected %d, got %d",
+				test.nodesToFind, test.percentageOfNodesToFind, test.numAllNodes, test.expected, got)
+		}
+	}
+}
+
+func TestNodeSamplerDeterministic(t *testing.T) {
+	nodes := map[string]*api.NodeInfo{}
+	for i := 0; i < 200; i++ {
+		name := fmt.Sprintf("n%03d", i)
+		nodes[name] = &api.NodeInfo{Name: name}
+	}
+	nodeList := GetNodeList(nodes)
+	task := &api.TaskInfo{Namespace: "c1", Name: "p1"}
+
+	// One of every three nodes fits the task, and the predicates take different time.
+	fits := func(index int) bool { return index%3 == 0 }
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		var index int
+		fmt.Sscanf(node.Name, "n%d", &index)
+		time.Sleep(time.Duration(index%7) * 100 * time.Microsecond)
+		if !fits(index) {
+			return fmt.Errorf("node %s is full", node.Name)
+		}
+		return nil
+	}
+
+	sampler := &NodeSampler{}
+	sampler.SetLimits(5, 0)
+
+	// The nodes found are the same as predicating the nodes one by one.
+	start := 0
+	for round := 0; round < 50; round++ {
+		var expected []string
+		next := start
+		for i := 0; i < len(nodeList) && len(expected) < 5; i++ {
+			index := (start + i) % len(nodeList)
+			if fits(index) {
+				expected = append(expected, nodeList[index].Name)
+				next = (index + 1) % len(nodeList)
+			}
+		}
+		start = next
+
+		var got []string
+		for _, node := range sampler.PredicateNodes(task, nodeList, predicateFn) {
+			got = append(got, node.Name)
+		}
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("round %d: expected nodes %v, got %v", round, expected, got)
+		}
+	}
+}
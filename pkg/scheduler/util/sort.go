@@ -22,13 +22,14 @@ import (
 	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
 )
 
-func SelectBestNode(nodeScores map[int][]*api.NodeInfo) []*api.NodeInfo {
+// SelectBestNode returns the nodes in the descending order of their total scores.
+func SelectBestNode(nodeScores map[float64][]*api.NodeInfo) []*api.NodeInfo {
 	var nodesInorder []*api.NodeInfo
-	var keys []int
+	var keys []float64
 	for key := range nodeScores {
 		keys = append(keys, key)
 	}
-	sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+	sort.Sort(sort.Reverse(sort.Float64Slice(keys)))
 	for _, key := range keys {
 		nodes := nodeScores[key]
 		nodesInorder = append(nodesInorder, nodes...)
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	_ "github.com/kubernetes-sigs/kube-batch/pkg/scheduler/actions"
+)
+
+func TestLoadSchedulerConfActions(t *testing.T) {
+	tests := []struct {
+		actions  string
+		expected []string
+		valid    bool
+	}{
+		{
+			actions:  "enqueue, allocate, backfill",
+			expected: []string{"enqueue", "allocate", "backfill"},
+			valid:    true,
+		},
+		{
+			actions:  "reclaim, preempt",
+			expected: []string{"reclaim", "preempt"},
+			valid:    true,
+		},
+		{
+			actions: "allocate, backfill",
+			valid:   false,
+		},
+		{
+			actions: "allocate, enqueue",
+			valid:   false,
+		},
+		{
+			actions: "enqueue, unknown",
+			valid:   false,
+		},
+	}
+
+	for _, test := range tests {
+		actions, _, _, _, err := loadSchedulerConf(`actions: "` + test.actions + `"`)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("actions %q: expected error, got nil", test.actions)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("actions %q: unexpected error: %v", test.actions, err)
+			continue
+		}
+
+		var names []string
+		for _, action := range actions {
+			names = append(names, action.Name())
+		}
+		if !reflect.DeepEqual(test.expected, names) {
+			t.Errorf("actions %q: expected %v, got %v", test.actions, test.expected, names)
+		}
+	}
+}